@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/pdcgo/order_service/order/draft_core"
+	"github.com/urfave/cli/v3"
+	"gorm.io/gorm"
+)
+
+type DraftsCleanupFunc cli.ActionFunc
+
+func NewDraftsCleanup(
+	db *gorm.DB,
+) DraftsCleanupFunc {
+	return func(ctx context.Context, c *cli.Command) error {
+		cleanup := draft_core.NewCleanup(db, time.Now())
+
+		var items []*draft_core.CleanupItem
+		var err error
+
+		dryRun := c.Bool("dry-run")
+		if dryRun {
+			items, err = cleanup.List(ctx)
+		} else {
+			items, err = cleanup.Run(ctx, c.Bool("archive"))
+		}
+
+		for _, item := range items {
+			slog.Info("draft cleanup",
+				"draft_id", item.ID,
+				"team_id", item.TeamID,
+				"order_ref_id", item.OrderRefID,
+				"created", item.Created,
+				"reason", item.Reason,
+				"dry_run", dryRun,
+			)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		slog.Info("draft cleanup selesai", "count", len(items), "dry_run", dryRun, "archive", c.Bool("archive"))
+		return nil
+	}
+}
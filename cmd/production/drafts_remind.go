@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/pdcgo/order_service/order/draft_core"
+	"github.com/urfave/cli/v3"
+	"gorm.io/gorm"
+)
+
+type DraftsRemindFunc cli.ActionFunc
+
+func NewDraftsRemind(
+	db *gorm.DB,
+) DraftsRemindFunc {
+	return func(ctx context.Context, c *cli.Command) error {
+		reminder := draft_core.NewReminder(db, time.Now())
+
+		flag := c.Bool("flag")
+		items, err := reminder.List(ctx, &draft_core.ReminderFilter{
+			Within:  time.Duration(c.Int("within")) * time.Hour,
+			OnlyNew: flag,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			slog.Info("draft deadline",
+				"draft_id", item.ID,
+				"team_id", item.TeamID,
+				"order_ref_id", item.OrderRefID,
+				"order_deadline", item.OrderDeadline,
+				"status", item.Status,
+			)
+		}
+
+		if !flag {
+			slog.Info("draft deadline selesai", "count", len(items), "flag", flag)
+			return nil
+		}
+
+		events, err := reminder.Remind(ctx, items)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			slog.Info("draft reminder",
+				"event_id", event.ID,
+				"team_id", event.TeamID,
+				"due_soon", event.DueSoon,
+				"overdue", event.Overdue,
+			)
+		}
+
+		slog.Info("draft deadline selesai", "count", len(items), "flag", flag, "events", len(events))
+		return nil
+	}
+}
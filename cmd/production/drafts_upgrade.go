@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/pdcgo/order_service/order/draft_core"
+	"github.com/urfave/cli/v3"
+	"gorm.io/gorm"
+)
+
+type DraftsUpgradeFunc cli.ActionFunc
+
+func NewDraftsUpgrade(
+	db *gorm.DB,
+) DraftsUpgradeFunc {
+	return func(ctx context.Context, c *cli.Command) error {
+		dryRun := c.Bool("dry-run")
+
+		report, err := draft_core.UpgradeAll(ctx, db, dryRun)
+
+		for _, failed := range report.Failed {
+			slog.Warn("draft upgrade gagal",
+				"draft_id", failed.DraftID,
+				"team_id", failed.TeamID,
+				"from_version", failed.FromVersion,
+				"reason", failed.Reason,
+			)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		slog.Info("draft upgrade selesai",
+			"converted", len(report.Converted),
+			"failed", len(report.Failed),
+			"dry_run", dryRun,
+		)
+		return nil
+	}
+}
@@ -65,6 +65,10 @@ type App *cli.Command
 func NewApp(
 	api ApiFunc,
 	orderShipped OrderShippedFunc,
+	draftsCleanup DraftsCleanupFunc,
+	draftsUpgrade DraftsUpgradeFunc,
+	draftsRemind DraftsRemindFunc,
+	tagRules TagRulesFunc,
 ) App {
 
 	return &cli.Command{
@@ -78,6 +82,59 @@ func NewApp(
 						Description: "check updated order shipped",
 						Action:      cli.ActionFunc(orderShipped),
 					},
+					{
+						Name:        "drafts-cleanup",
+						Description: "hapus draft yang sudah lewat ttl atau sudah jadi order",
+						Flags: []cli.Flag{
+							&cli.BoolFlag{
+								Name:  "dry-run",
+								Usage: "hanya list draft yang akan dihapus",
+							},
+							&cli.BoolFlag{
+								Name:  "archive",
+								Usage: "simpan draft ke draft_order_archives sebelum dihapus",
+							},
+						},
+						Action: cli.ActionFunc(draftsCleanup),
+					},
+					{
+						Name:        "drafts-upgrade",
+						Description: "upgrade draft versi lama ke payload proto",
+						Flags: []cli.Flag{
+							&cli.BoolFlag{
+								Name:  "dry-run",
+								Usage: "hanya cek konversi tanpa menyimpan",
+							},
+						},
+						Action: cli.ActionFunc(draftsUpgrade),
+					},
+					{
+						Name:        "drafts-remind",
+						Description: "list draft yang deadline nya dekat atau sudah lewat",
+						Flags: []cli.Flag{
+							&cli.IntFlag{
+								Name:  "within",
+								Usage: "deadline dalam N jam ke depan",
+								Value: 24,
+							},
+							&cli.BoolFlag{
+								Name:  "flag",
+								Usage: "tandai draft dan buat event reminder per team",
+							},
+						},
+						Action: cli.ActionFunc(draftsRemind),
+					},
+					{
+						Name:        "tag-rules",
+						Description: "evaluasi rule tag otomatis ke order team",
+						Flags: []cli.Flag{
+							&cli.IntFlag{
+								Name:  "team",
+								Usage: "hanya team ini, 0 untuk semua team yang punya rule aktif",
+							},
+						},
+						Action: cli.ActionFunc(tagRules),
+					},
 				},
 			},
 		},
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/pdcgo/order_service/order/tag_rule"
+	"github.com/urfave/cli/v3"
+	"gorm.io/gorm"
+)
+
+type TagRulesFunc cli.ActionFunc
+
+func NewTagRules(
+	db *gorm.DB,
+) TagRulesFunc {
+	return func(ctx context.Context, c *cli.Command) error {
+		result, err := tag_rule.EvaluateAll(ctx, db, uint(c.Int("team")), time.Now())
+		if err != nil {
+			return err
+		}
+
+		slog.Info("tag rule selesai",
+			"orders", result.Orders,
+			"matched", result.Matched,
+			"unmatched", result.Unmatched,
+		)
+		return nil
+	}
+}
@@ -36,6 +36,10 @@ func InitializeApp() (App, error) {
 		NewCreateTokenFromUsername,
 		NewHelper,
 		NewOrderShipped,
+		NewDraftsCleanup,
+		NewDraftsUpgrade,
+		NewDraftsRemind,
+		NewTagRules,
 
 		NewApi,
 		NewApp,
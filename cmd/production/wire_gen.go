@@ -47,6 +47,10 @@ func InitializeApp() (App, error) {
 	setAuthorization := NewSetAuthorization(createTokenFromUsername, appConfig)
 	helper := NewHelper(createTokenFromUsername, setAuthorization)
 	orderShippedFunc := NewOrderShipped(db, appConfig, defaultClientInterceptor, helper)
-	app := NewApp(apiFunc, orderShippedFunc)
+	draftsCleanupFunc := NewDraftsCleanup(db)
+	draftsUpgradeFunc := NewDraftsUpgrade(db)
+	draftsRemindFunc := NewDraftsRemind(db)
+	tagRulesFunc := NewTagRules(db)
+	app := NewApp(apiFunc, orderShippedFunc, draftsCleanupFunc, draftsUpgradeFunc, draftsRemindFunc, tagRulesFunc)
 	return app, nil
 }
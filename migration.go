@@ -1,11 +1,46 @@
 package order_service
 
-import "gorm.io/gorm"
+import (
+	"github.com/pdcgo/order_service/order/draft_core"
+	"github.com/pdcgo/order_service/order/order_core"
+	"github.com/pdcgo/order_service/order/order_view"
+	"github.com/pdcgo/order_service/order/tag_core"
+	"github.com/pdcgo/order_service/order/tag_rule"
+	"gorm.io/gorm"
+)
 
 type MigrationFunc func(db *gorm.DB) error
 
 func NewMigration() MigrationFunc {
 	return func(db *gorm.DB) error {
-		return nil
+		err := order_core.MigrateCurrency(db)
+		if err != nil {
+			return err
+		}
+
+		err = order_core.MigrateAdjustmentLog(db)
+		if err != nil {
+			return err
+		}
+
+		err = draft_core.Migrate(db)
+		if err != nil {
+			return err
+		}
+
+		err = tag_core.Migrate(db)
+		if err != nil {
+			return err
+		}
+
+		return db.AutoMigrate(
+			&draft_core.DraftTTL{},
+			&draft_core.DraftOrderArchive{},
+			&draft_core.DraftUpgradeFailure{},
+			&draft_core.DraftReminderEvent{},
+			&tag_rule.TagRule{},
+			&tag_core.OrderTagHistory{},
+			&order_view.SavedView{},
+		)
 	}
 }
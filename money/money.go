@@ -0,0 +1,255 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type Currency string
+
+const (
+	IDR Currency = "IDR"
+	MYR Currency = "MYR"
+	SGD Currency = "SGD"
+	PHP Currency = "PHP"
+	THB Currency = "THB"
+	VND Currency = "VND"
+)
+
+// currency default untuk data lama yang belum punya currency
+const DefaultCurrency = IDR
+
+func (Currency) EnumList() []string {
+	return []string{
+		"IDR",
+		"MYR",
+		"SGD",
+		"PHP",
+		"THB",
+		"VND",
+	}
+}
+
+// Exponent jumlah digit minor unit, IDR dan VND tidak punya sen
+func (c Currency) Exponent() int {
+	return c.Rounding().Exponent
+}
+
+func (c Currency) Rounding() Rounding {
+	rule, ok := roundingRules[c.OrDefault()]
+	if !ok {
+		return Rounding{Exponent: 2, Mode: RoundHalfUp}
+	}
+	return rule
+}
+
+func (c Currency) Validate() error {
+	for _, item := range c.EnumList() {
+		if string(c) == item {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("currency %s not supported", c)
+}
+
+// OrDefault dipakai untuk membaca kolom currency yang masih kosong
+func (c Currency) OrDefault() Currency {
+	if c == "" {
+		return DefaultCurrency
+	}
+	return c
+}
+
+type RoundingMode int
+
+const (
+	// .5 dibulatkan menjauhi nol
+	RoundHalfUp RoundingMode = iota
+	// .5 dibulatkan ke angka genap terdekat (bankers rounding)
+	RoundHalfEven
+)
+
+type Rounding struct {
+	Exponent int
+	Mode     RoundingMode
+}
+
+// aturan pembulatan mengikuti settlement marketplace tiap negara
+var roundingRules = map[Currency]Rounding{
+	IDR: {Exponent: 0, Mode: RoundHalfUp},
+	VND: {Exponent: 0, Mode: RoundHalfUp},
+	MYR: {Exponent: 2, Mode: RoundHalfUp},
+	SGD: {Exponent: 2, Mode: RoundHalfUp},
+	PHP: {Exponent: 2, Mode: RoundHalfEven},
+	THB: {Exponent: 2, Mode: RoundHalfEven},
+}
+
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// Amount nilai uang dalam minor unit supaya tidak ada selisih float
+type Amount struct {
+	Currency Currency
+	Minor    int64
+}
+
+func New(currency Currency, minor int64) Amount {
+	return Amount{
+		Currency: currency.OrDefault(),
+		Minor:    minor,
+	}
+}
+
+// FromUnits dari nilai utuh tanpa pecahan (mis. field proto uint64), tidak lewat float
+func FromUnits(currency Currency, units int64) Amount {
+	currency = currency.OrDefault()
+	return New(currency, units*int64(math.Pow10(currency.Exponent())))
+}
+
+// FromFloat konversi dari field float (proto dan kolom lama).
+// float diubah ke representasi desimal terpendek dulu supaya 1.005 tetap 1.005
+// bukan 1.00499999, baru dibulatkan sesuai aturan currency.
+func FromFloat(currency Currency, value float64) Amount {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return New(currency, 0)
+	}
+
+	amount, _ := Parse(currency, strconv.FormatFloat(value, 'f', -1, 64))
+	return amount
+}
+
+// Parse membaca angka desimal seperti "-1234.565" lalu dibulatkan sesuai aturan currency
+func Parse(currency Currency, value string) (Amount, error) {
+	currency = currency.OrDefault()
+	rule := currency.Rounding()
+
+	raw := strings.TrimSpace(value)
+	negative := false
+	switch {
+	case strings.HasPrefix(raw, "-"):
+		negative = true
+		raw = raw[1:]
+	case strings.HasPrefix(raw, "+"):
+		raw = raw[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(raw, ".")
+	if intPart == "" {
+		intPart = "0"
+	}
+
+	if !isDigits(intPart) || !isDigits(fracPart) {
+		return New(currency, 0), fmt.Errorf("invalid amount %s", value)
+	}
+
+	// ambil digit sebanyak exponent, sisanya untuk pembulatan
+	for len(fracPart) < rule.Exponent {
+		fracPart += "0"
+	}
+
+	kept := fracPart[:rule.Exponent]
+	rest := fracPart[rule.Exponent:]
+
+	minor, err := strconv.ParseInt(intPart+kept, 10, 64)
+	if err != nil {
+		return New(currency, 0), fmt.Errorf("invalid amount %s: %w", value, err)
+	}
+
+	if roundUp(minor, rest, rule.Mode) {
+		minor++
+	}
+
+	if negative {
+		minor = -minor
+	}
+
+	return New(currency, minor), nil
+}
+
+func roundUp(minor int64, rest string, mode RoundingMode) bool {
+	if rest == "" || rest[0] < '5' {
+		return false
+	}
+
+	if rest[0] > '5' || strings.TrimRight(rest[1:], "0") != "" {
+		return true
+	}
+
+	// tepat di tengah
+	switch mode {
+	case RoundHalfEven:
+		return minor%2 != 0
+	default:
+		return true
+	}
+}
+
+func isDigits(s string) bool {
+	for _, ch := range s {
+		if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (a Amount) Float() float64 {
+	return float64(a.Minor) / math.Pow10(a.Currency.OrDefault().Exponent())
+}
+
+func (a Amount) IsZero() bool {
+	return a.Minor == 0
+}
+
+func (a Amount) IsNegative() bool {
+	return a.Minor < 0
+}
+
+func (a Amount) Equal(b Amount) bool {
+	return a.Currency.OrDefault() == b.Currency.OrDefault() && a.Minor == b.Minor
+}
+
+func (a Amount) Add(b Amount) (Amount, error) {
+	if a.Currency.OrDefault() != b.Currency.OrDefault() {
+		return a, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, a.Currency, b.Currency)
+	}
+
+	return New(a.Currency, a.Minor+b.Minor), nil
+}
+
+func (a Amount) Sub(b Amount) (Amount, error) {
+	return a.Add(b.Neg())
+}
+
+func (a Amount) Neg() Amount {
+	return New(a.Currency, -a.Minor)
+}
+
+func (a Amount) Abs() Amount {
+	if a.Minor < 0 {
+		return a.Neg()
+	}
+	return a
+}
+
+func (a Amount) String() string {
+	currency := a.Currency.OrDefault()
+	exp := currency.Exponent()
+
+	minor := a.Minor
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+
+	if exp == 0 {
+		return fmt.Sprintf("%s %s%d", currency, sign, minor)
+	}
+
+	scale := int64(math.Pow10(exp))
+	return fmt.Sprintf("%s %s%d.%0*d", currency, sign, minor/scale, exp, minor%scale)
+}
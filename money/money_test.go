@@ -0,0 +1,86 @@
+package money_test
+
+import (
+	"testing"
+
+	"github.com/pdcgo/order_service/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromFloat(t *testing.T) {
+	t.Run("float yang tidak presisi tetap exact", func(t *testing.T) {
+		amount := money.FromFloat(money.MYR, 0.1+0.2)
+		assert.Equal(t, int64(30), amount.Minor)
+		assert.True(t, amount.Equal(money.FromFloat(money.MYR, 0.3)))
+	})
+
+	t.Run("1.005 dibulatkan ke atas bukan ke bawah", func(t *testing.T) {
+		amount := money.FromFloat(money.MYR, 1.005)
+		assert.Equal(t, int64(101), amount.Minor)
+	})
+
+	t.Run("IDR tidak punya sen", func(t *testing.T) {
+		assert.Equal(t, int64(15001), money.FromFloat(money.IDR, 15000.5).Minor)
+		assert.Equal(t, int64(15000), money.FromFloat(money.IDR, 15000.4999).Minor)
+		assert.Equal(t, int64(100000), money.FromFloat(money.IDR, 99999.9999999).Minor)
+	})
+
+	t.Run("negatif dibulatkan menjauhi nol", func(t *testing.T) {
+		assert.Equal(t, int64(-15001), money.FromFloat(money.IDR, -15000.5).Minor)
+		assert.Equal(t, int64(-101), money.FromFloat(money.MYR, -1.005).Minor)
+	})
+
+	t.Run("currency kosong pakai default", func(t *testing.T) {
+		amount := money.FromFloat("", 1200)
+		assert.Equal(t, money.DefaultCurrency, amount.Currency)
+		assert.Equal(t, int64(1200), amount.Minor)
+	})
+}
+
+func TestFromUnits(t *testing.T) {
+	assert.Equal(t, int64(15000), money.FromUnits(money.IDR, 15000).Minor)
+	assert.Equal(t, int64(1500000), money.FromUnits(money.MYR, 15000).Minor)
+	assert.Equal(t, float64(15000), money.FromUnits(money.MYR, 15000).Float())
+}
+
+func TestParse(t *testing.T) {
+	t.Run("half even untuk PHP", func(t *testing.T) {
+		amount, err := money.Parse(money.PHP, "2.345")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(234), amount.Minor)
+
+		amount, err = money.Parse(money.PHP, "2.355")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(236), amount.Minor)
+
+		amount, err = money.Parse(money.PHP, "2.3451")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(235), amount.Minor)
+	})
+
+	t.Run("format salah", func(t *testing.T) {
+		_, err := money.Parse(money.IDR, "12.000,00")
+		assert.NotNil(t, err)
+	})
+}
+
+func TestArithmetic(t *testing.T) {
+	t.Run("selisih tidak menghasilkan angka phantom", func(t *testing.T) {
+		total := money.FromFloat(money.IDR, 100000)
+		fund := money.FromFloat(money.IDR, 99999.9999999)
+
+		diff, err := total.Sub(fund)
+		assert.Nil(t, err)
+		assert.True(t, diff.IsZero())
+	})
+
+	t.Run("currency berbeda tidak bisa dijumlah", func(t *testing.T) {
+		_, err := money.FromFloat(money.IDR, 1000).Add(money.FromFloat(money.MYR, 10))
+		assert.ErrorIs(t, err, money.ErrCurrencyMismatch)
+	})
+
+	t.Run("string", func(t *testing.T) {
+		assert.Equal(t, "MYR -0.05", money.FromFloat(money.MYR, -0.05).String())
+		assert.Equal(t, "IDR 1500", money.FromFloat(money.IDR, 1500).String())
+	})
+}
@@ -2,10 +2,12 @@ package order
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/money"
+	"github.com/pdcgo/order_service/order/order_core"
+	"github.com/pdcgo/order_service/order_errors"
 	"github.com/pdcgo/schema/services/access_iface/v1"
 	"github.com/pdcgo/schema/services/order_iface/v1"
 	"github.com/pdcgo/schema/services/revenue_iface/v1"
@@ -34,7 +36,7 @@ func (o *orderServiceImpl) ChangeEstRevenue(ctx context.Context, req *connect.Re
 	default:
 		domainID = uint(source.TeamId)
 		if pay.TeamId != source.TeamId {
-			return nil, fmt.Errorf("cannot cross team")
+			return nil, order_errors.WrongTeam(pay.OrderId, pay.TeamId)
 		}
 	}
 
@@ -73,13 +75,24 @@ func (o *orderServiceImpl) ChangeEstRevenue(ctx context.Context, req *connect.Re
 
 		// filter hanya boleh diedit sekitar satu minggu
 		if time.Since(ord.CreatedAt) > time.Hour*24*7 {
-			return fmt.Errorf("order %s sudah lebih dari satu minggu", ord.OrderRefID)
+			return order_errors.EditExpired(uint64(ord.ID), ord.OrderRefID, 7)
 		}
 
+		currency, err := order_core.GetOrderCurrency(tx, ord.ID)
+		if err != nil {
+			return err
+		}
+
+		if pay.Currency != "" && money.Currency(pay.Currency) != currency {
+			return order_errors.CurrencyMismatch(uint64(ord.ID), ord.OrderRefID, string(currency), pay.Currency)
+		}
+
+		estRevenue := money.FromUnits(currency, int64(pay.EstRevenueAmount))
+
 		err = tx.
 			Model(&db_models.Order{}).
 			Where("id = ?", pay.OrderId).
-			Update("order_mp_total", pay.EstRevenueAmount).
+			Update("order_mp_total", estRevenue.Float()).
 			Error
 
 		return err
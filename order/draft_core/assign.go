@@ -0,0 +1,55 @@
+package draft_core
+
+import (
+	"github.com/pdcgo/order_service/order_errors"
+	"gorm.io/gorm"
+)
+
+// DraftManage permission untuk role team yang boleh mengubah, menghapus
+// dan memindahkan draft milik user lain di team yang sama
+type DraftManage struct{}
+
+func (d *DraftManage) GetEntityID() string {
+	return "draft_order_manage"
+}
+
+// IsOwner pembuat atau assignee draft
+func (d *DraftOrder) IsOwner(userID uint) bool {
+	return d.UserID == userID || d.AssigneeID == userID
+}
+
+// OwnerScope filter draft milik user, dipakai kalau user tidak punya DraftManage
+func OwnerScope(userID uint) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("user_id = ? OR assignee_id = ?", userID, userID)
+	}
+}
+
+// Reassign pindahkan draft ke user lain dalam satu team, user dianggap anggota
+// team kalau punya role di domain team
+func Reassign(tx *gorm.DB, teamID uint, draftIDs []uint, assigneeID uint) (int64, error) {
+	var member int64
+	err := tx.
+		Table("user_roles ur").
+		Joins("JOIN roles r ON r.id = ur.role_id").
+		Where("ur.user_id = ?", assigneeID).
+		Where("r.domain_id = ?", teamID).
+		Count(&member).
+		Error
+
+	if err != nil {
+		return 0, err
+	}
+
+	if member == 0 {
+		return 0, order_errors.AssigneeNotInTeam(uint64(assigneeID), uint64(teamID))
+	}
+
+	res := tx.
+		Model(&DraftOrder{}).
+		Where("team_id = ?", teamID).
+		Where("id IN ?", draftIDs).
+		Update("assignee_id", assigneeID)
+
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,89 @@
+package draft_core_test
+
+import (
+	"testing"
+
+	"github.com/pdcgo/order_service/order/draft_core"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+	"github.com/pdcgo/shared/pkg/moretest"
+	"github.com/pdcgo/shared/pkg/moretest/moretest_mock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestReassignDraft(t *testing.T) {
+	var db gorm.DB
+
+	var migration moretest.SetupFunc = func(t *testing.T) func() error {
+		err := db.AutoMigrate(
+			&draft_core.DraftOrder{},
+			&authorization_iface.Role{},
+			&authorization_iface.UserRole{},
+		)
+		assert.Nil(t, err)
+		return nil
+	}
+
+	var seed moretest.SetupFunc = func(t *testing.T) func() error {
+		drafts := []*draft_core.DraftOrder{
+			{ID: 1, TeamID: 3, UserID: 7, AssigneeID: 7, OrderRefID: "A1"},
+			{ID: 2, TeamID: 3, UserID: 7, AssigneeID: 7, OrderRefID: "A2"},
+			{ID: 3, TeamID: 4, UserID: 7, AssigneeID: 7, OrderRefID: "B1"},
+		}
+		err := db.Save(&drafts).Error
+		assert.Nil(t, err)
+
+		err = db.Save(&authorization_iface.Role{ID: 1, Key: "cs", DomainID: 3}).Error
+		assert.Nil(t, err)
+		err = db.Save(&authorization_iface.UserRole{ID: 1, RoleID: 1, UserID: 8}).Error
+		assert.Nil(t, err)
+		return nil
+	}
+
+	moretest.Suite(t, "testing reassign draft",
+		moretest.SetupListFunc{
+			moretest_mock.MockSqliteDatabase(&db),
+			migration,
+			seed,
+		},
+		func(t *testing.T) {
+			t.Run("pindah ke anggota team", func(t *testing.T) {
+				count, err := draft_core.Reassign(&db, 3, []uint{1, 2, 3}, 8)
+				assert.Nil(t, err)
+				assert.Equal(t, int64(2), count)
+
+				// draft team lain tidak ikut pindah
+				var draft draft_core.DraftOrder
+				err = db.First(&draft, 3).Error
+				assert.Nil(t, err)
+				assert.Equal(t, uint(7), draft.AssigneeID)
+			})
+
+			t.Run("assignee bukan anggota team", func(t *testing.T) {
+				_, err := draft_core.Reassign(&db, 4, []uint{3}, 8)
+				assert.ErrorIs(t, err, order_errors.ErrAssigneeNotInTeam)
+			})
+
+			t.Run("pembuat dan assignee tetap bisa akses", func(t *testing.T) {
+				var ids []uint
+				err := db.
+					Model(&draft_core.DraftOrder{}).
+					Where("team_id = ?", 3).
+					Scopes(draft_core.OwnerScope(7)).
+					Order("id asc").
+					Pluck("id", &ids).
+					Error
+				assert.Nil(t, err)
+				assert.Equal(t, []uint{1, 2}, ids)
+
+				_, err = draft_core.Update(&db, &draft_core.UpdateDraft{
+					DraftID: 1,
+					TeamID:  3,
+					OwnerID: 9,
+				})
+				assert.ErrorIs(t, err, order_errors.ErrDraftNotOwned)
+			})
+		},
+	)
+}
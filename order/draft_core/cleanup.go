@@ -0,0 +1,207 @@
+package draft_core
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// TTL default kalau team belum setting
+const DefaultTTLDays = 30
+
+// DraftTTL umur draft per team
+type DraftTTL struct {
+	TeamID    uint      `json:"team_id" gorm:"primarykey;autoIncrement:false"`
+	TTLDays   int       `json:"ttl_days"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func SetTTL(tx *gorm.DB, teamID uint, days int) error {
+	return tx.Save(&DraftTTL{
+		TeamID:    teamID,
+		TTLDays:   days,
+		UpdatedAt: time.Now(),
+	}).Error
+}
+
+type CleanupReason string
+
+const (
+	CleanupExpired CleanupReason = "expired"
+	// order_ref_id sudah jadi order
+	CleanupOrdered CleanupReason = "ordered"
+)
+
+// DraftOrderArchive salinan draft yang dibersihkan dengan mode archive
+type DraftOrderArchive struct {
+	ID uint `json:"id" gorm:"primarykey;autoIncrement:false"`
+
+	TeamID       uint           `json:"team_id" gorm:"index"`
+	UserID       uint           `json:"user_id"`
+	DraftVersion string         `json:"draft_version"`
+	OrderRefID   string         `json:"order_ref_id" gorm:"index"`
+	OrderMpID    uint           `json:"order_mp_id"`
+	OrderTotal   int            `json:"order_total"`
+	OrderFrom    string         `json:"order_from"`
+	OrderPayload datatypes.JSON `json:"order_payload"`
+	MpProducts   datatypes.JSON `json:"mp_products"`
+	Created      time.Time      `json:"created"`
+
+	ArchiveReason CleanupReason `json:"archive_reason"`
+	ArchivedAt    time.Time     `json:"archived_at"`
+}
+
+type CleanupItem struct {
+	ID         uint          `json:"id"`
+	TeamID     uint          `json:"team_id"`
+	OrderRefID string        `json:"order_ref_id"`
+	Created    time.Time     `json:"created"`
+	Reason     CleanupReason `json:"reason"`
+}
+
+type Cleanup struct {
+	db        *gorm.DB
+	now       time.Time
+	batchSize int
+}
+
+func NewCleanup(db *gorm.DB, now time.Time) *Cleanup {
+	return &Cleanup{
+		db:        db,
+		now:       now,
+		batchSize: 500,
+	}
+}
+
+// OrderedCondition draft yang ref id nya sudah jadi order di team yang sama
+func OrderedCondition(table string) string {
+	return "EXISTS (SELECT 1 FROM orders o WHERE o.team_id = " + table + ".team_id AND o.order_ref_id = " + table + ".order_ref_id)"
+}
+
+// candidateQuery draft yang sudah lewat ttl team atau ref id nya sudah ada di orders
+// (sama dengan pengecekan OrderDraftCheck: team_id dan order_ref_id)
+func (c *Cleanup) candidateQuery(tx *gorm.DB) (*gorm.DB, error) {
+	ttls := []*DraftTTL{}
+	err := tx.
+		Model(&DraftTTL{}).
+		Find(&ttls).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	expired := tx.Where("d.created < ?", c.now.AddDate(0, 0, -DefaultTTLDays))
+	if len(ttls) != 0 {
+		teamIDs := make([]uint, len(ttls))
+		for i, ttl := range ttls {
+			teamIDs[i] = ttl.TeamID
+		}
+
+		expired = tx.
+			Where("d.team_id NOT IN ? AND d.created < ?", teamIDs, c.now.AddDate(0, 0, -DefaultTTLDays))
+
+		for _, ttl := range ttls {
+			expired = expired.
+				Or("d.team_id = ? AND d.created < ?", ttl.TeamID, c.now.AddDate(0, 0, -ttl.TTLDays))
+		}
+	}
+
+	ordered := OrderedCondition("d")
+
+	query := tx.
+		Table("draft_orders d").
+		Select([]string{
+			"d.id",
+			"d.team_id",
+			"d.order_ref_id",
+			"d.created",
+			"CASE WHEN " + ordered + " THEN '" + string(CleanupOrdered) + "' ELSE '" + string(CleanupExpired) + "' END AS reason",
+		}).
+		Where(tx.Where(ordered).Or(expired)).
+		Order("d.id asc")
+
+	return query, nil
+}
+
+// List dipakai untuk dry run
+func (c *Cleanup) List(ctx context.Context) ([]*CleanupItem, error) {
+	items := []*CleanupItem{}
+
+	query, err := c.candidateQuery(c.db.WithContext(ctx))
+	if err != nil {
+		return items, err
+	}
+
+	err = query.Find(&items).Error
+	return items, err
+}
+
+// Run hapus atau archive draft per batch, mengembalikan draft yang dibersihkan
+func (c *Cleanup) Run(ctx context.Context, archive bool) ([]*CleanupItem, error) {
+	items, err := c.List(ctx)
+	if err != nil {
+		return items, err
+	}
+
+	for start := 0; start < len(items); start += c.batchSize {
+		end := min(start+c.batchSize, len(items))
+		batch := items[start:end]
+
+		err = c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if archive {
+				err := c.archive(tx, batch)
+				if err != nil {
+					return err
+				}
+			}
+
+			ids := make([]uint, len(batch))
+			for i, item := range batch {
+				ids[i] = item.ID
+			}
+
+			return tx.
+				Where("id IN ?", ids).
+				Delete(&DraftOrder{}).
+				Error
+		})
+
+		if err != nil {
+			return items[:start], err
+		}
+	}
+
+	return items, nil
+}
+
+func (c *Cleanup) archive(tx *gorm.DB, items []*CleanupItem) error {
+	reasons := map[CleanupReason][]uint{}
+	for _, item := range items {
+		reasons[item.Reason] = append(reasons[item.Reason], item.ID)
+	}
+
+	for reason, ids := range reasons {
+		err := tx.Exec(`
+			INSERT INTO draft_order_archives (
+				id, team_id, user_id, draft_version, order_ref_id, order_mp_id,
+				order_total, order_from, order_payload, mp_products, created,
+				archive_reason, archived_at
+			)
+			SELECT
+				id, team_id, user_id, draft_version, order_ref_id, order_mp_id,
+				order_total, order_from, order_payload, mp_products, created,
+				?, ?
+			FROM draft_orders
+			WHERE id IN ?
+		`, reason, c.now, ids).Error
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
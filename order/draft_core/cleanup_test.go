@@ -0,0 +1,103 @@
+package draft_core_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pdcgo/order_service/order/draft_core"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/pkg/moretest"
+	"github.com/pdcgo/shared/pkg/moretest/moretest_mock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestCleanup(t *testing.T) {
+	var db gorm.DB
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	var migration moretest.SetupFunc = func(t *testing.T) func() error {
+		err := db.AutoMigrate(
+			&db_models.Order{},
+			&draft_core.DraftOrder{},
+			&draft_core.DraftTTL{},
+			&draft_core.DraftOrderArchive{},
+		)
+		assert.Nil(t, err)
+		return nil
+	}
+
+	var seed moretest.SetupFunc = func(t *testing.T) func() error {
+		drafts := []*draft_core.DraftOrder{
+			// team 1 default ttl 30 hari
+			{ID: 1, TeamID: 1, OrderRefID: "A1", Created: now.AddDate(0, 0, -40)},
+			{ID: 2, TeamID: 1, OrderRefID: "A2", Created: now.AddDate(0, 0, -10)},
+			// sudah jadi order
+			{ID: 3, TeamID: 1, OrderRefID: "A3", Created: now.AddDate(0, 0, -1)},
+			// team 2 ttl 7 hari
+			{ID: 4, TeamID: 2, OrderRefID: "B1", Created: now.AddDate(0, 0, -10)},
+			{ID: 5, TeamID: 2, OrderRefID: "B2", Created: now.AddDate(0, 0, -3)},
+			// ref sama tapi order milik team lain
+			{ID: 6, TeamID: 2, OrderRefID: "A3", Created: now.AddDate(0, 0, -1)},
+		}
+		err := db.Save(&drafts).Error
+		assert.Nil(t, err)
+
+		err = db.Save(&db_models.Order{ID: 1, TeamID: 1, OrderRefID: "A3"}).Error
+		assert.Nil(t, err)
+
+		err = draft_core.SetTTL(&db, 2, 7)
+		assert.Nil(t, err)
+		return nil
+	}
+
+	moretest.Suite(t, "testing draft cleanup",
+		moretest.SetupListFunc{
+			moretest_mock.MockSqliteDatabase(&db),
+			migration,
+			seed,
+		},
+		func(t *testing.T) {
+			cleanup := draft_core.NewCleanup(&db, now)
+
+			t.Run("dry run hanya list", func(t *testing.T) {
+				items, err := cleanup.List(context.Background())
+				assert.Nil(t, err)
+
+				reasons := map[uint]draft_core.CleanupReason{}
+				for _, item := range items {
+					reasons[item.ID] = item.Reason
+				}
+				assert.Equal(t, map[uint]draft_core.CleanupReason{
+					1: draft_core.CleanupExpired,
+					3: draft_core.CleanupOrdered,
+					4: draft_core.CleanupExpired,
+				}, reasons)
+
+				var count int64
+				err = db.Model(&draft_core.DraftOrder{}).Count(&count).Error
+				assert.Nil(t, err)
+				assert.Equal(t, int64(6), count)
+			})
+
+			t.Run("archive lalu hapus", func(t *testing.T) {
+				items, err := cleanup.Run(context.Background(), true)
+				assert.Nil(t, err)
+				assert.Len(t, items, 3)
+
+				ids := []uint{}
+				err = db.Model(&draft_core.DraftOrder{}).Order("id asc").Pluck("id", &ids).Error
+				assert.Nil(t, err)
+				assert.Equal(t, []uint{2, 5, 6}, ids)
+
+				archives := []*draft_core.DraftOrderArchive{}
+				err = db.Order("id asc").Find(&archives).Error
+				assert.Nil(t, err)
+				assert.Len(t, archives, 3)
+				assert.Equal(t, "A3", archives[1].OrderRefID)
+				assert.Equal(t, draft_core.CleanupOrdered, archives[1].ArchiveReason)
+			})
+		},
+	)
+}
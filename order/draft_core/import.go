@@ -0,0 +1,586 @@
+package draft_core
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/pkg/excel_reader"
+)
+
+func importErr(format string, args ...any) error {
+	return order_errors.ImportInvalid(fmt.Sprintf(format, args...))
+}
+
+// exportColumns nama header export per marketplace, beberapa alias karena export
+// berubah tergantung bahasa seller center
+type exportColumns struct {
+	ref           []string
+	orderTime     []string
+	product       []string
+	variation     []string
+	count         []string
+	total         []string
+	receipt       []string
+	buyer         []string
+	deadline      []string
+	name          []string
+	phone         []string
+	province      []string
+	city          []string
+	district      []string
+	postalCode    []string
+	address       []string
+	descRow       bool // tiktok ada baris keterangan setelah header
+	totalPerItem  bool // lazada total per item, harus dijumlah
+	countPerItems bool // lazada tidak ada kolom jumlah, 1 baris 1 barang
+}
+
+var exportMaps = map[db_models.OrderMpType]*exportColumns{
+	db_models.OrderMpShopee: {
+		ref:        []string{"No. Pesanan", "Order ID"},
+		orderTime:  []string{"Waktu Pesanan Dibuat", "Order Creation Date"},
+		product:    []string{"Nama Produk", "Product Name"},
+		variation:  []string{"Nama Variasi", "Variation Name"},
+		count:      []string{"Jumlah", "Quantity"},
+		total:      []string{"Total Pembayaran", "Grand Total"},
+		receipt:    []string{"No. Resi", "Tracking Number*"},
+		buyer:      []string{"Username (Pembeli)", "Username (Buyer)"},
+		deadline:   []string{"Pesanan Harus Dikirimkan Sebelum (Menghindari keterlambatan)", "Ship Time"},
+		name:       []string{"Nama Penerima", "Receiver Name"},
+		phone:      []string{"No. Telepon", "Phone Number"},
+		province:   []string{"Provinsi", "Province"},
+		city:       []string{"Kota/Kabupaten", "City"},
+		district:   []string{"Kecamatan", "District"},
+		postalCode: []string{"Kode Pos", "Zip Code"},
+		address:    []string{"Alamat Pengiriman", "Delivery Address"},
+	},
+	db_models.OrderMpTiktok: {
+		ref:        []string{"Order ID"},
+		orderTime:  []string{"Created Time"},
+		product:    []string{"Product Name"},
+		variation:  []string{"Variation"},
+		count:      []string{"Quantity"},
+		total:      []string{"Order Amount"},
+		receipt:    []string{"Tracking ID"},
+		buyer:      []string{"Buyer Username"},
+		deadline:   []string{"Shipping Deadline"},
+		name:       []string{"Recipient"},
+		phone:      []string{"Phone #"},
+		province:   []string{"Province"},
+		city:       []string{"Regency and City"},
+		district:   []string{"Districts"},
+		postalCode: []string{"Zipcode"},
+		address:    []string{"Detail Address"},
+		descRow:    true,
+	},
+	db_models.OrderMpTokopedia: {
+		ref:        []string{"Nomor Invoice", "Invoice"},
+		orderTime:  []string{"Tanggal Pembayaran", "Payment Date"},
+		product:    []string{"Nama Produk", "Product Name"},
+		variation:  []string{"Tipe Produk", "Product Type"},
+		count:      []string{"Jumlah Produk Dibeli", "Quantity"},
+		total:      []string{"Total Penjualan (IDR)", "Total Amount"},
+		receipt:    []string{"No Resi / Kode Booking", "AWB"},
+		buyer:      []string{"Nama Pembeli", "Customer Name"},
+		name:       []string{"Nama Penerima", "Recipient"},
+		phone:      []string{"No Telp Penerima", "Recipient Number"},
+		province:   []string{"Provinsi", "Province"},
+		city:       []string{"Kota", "City"},
+		postalCode: []string{"Kode Pos", "Postal Code"},
+		address:    []string{"Alamat Pengiriman", "Recipient Address"},
+	},
+	db_models.OrderMpLazada: {
+		ref:           []string{"orderNumber"},
+		orderTime:     []string{"createTime"},
+		product:       []string{"itemName"},
+		variation:     []string{"variation"},
+		total:         []string{"paidPrice"},
+		receipt:       []string{"trackingCode"},
+		buyer:         []string{"customerName"},
+		deadline:      []string{"promisedShippingTime"},
+		name:          []string{"shippingName"},
+		phone:         []string{"shippingPhone"},
+		province:      []string{"shippingRegion"},
+		city:          []string{"shippingCity"},
+		district:      []string{"shippingAddress5"},
+		postalCode:    []string{"shippingPostCode"},
+		address:       []string{"shippingAddress"},
+		totalPerItem:  true,
+		countPerItems: true,
+	},
+}
+
+// ImportOrder satu order hasil export, bisa dari beberapa baris (1 baris per produk)
+type ImportOrder struct {
+	Row        int
+	Data       *order_iface.DraftOrderData
+	MpProducts []*order_iface.MpProductItem
+}
+
+type ImportRowError struct {
+	Row        int
+	OrderRefID string
+	Message    string
+}
+
+type ImportResult struct {
+	Orders []*ImportOrder
+	Errors []*ImportRowError
+}
+
+type columnIndex map[string]int
+
+func (c columnIndex) get(row []string, key string) string {
+	i, ok := c[key]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+func normalizeHeader(s string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(s, "\ufeff")))
+}
+
+func (cols *exportColumns) fields() map[string][]string {
+	return map[string][]string{
+		"ref":         cols.ref,
+		"order_time":  cols.orderTime,
+		"product":     cols.product,
+		"variation":   cols.variation,
+		"count":       cols.count,
+		"total":       cols.total,
+		"receipt":     cols.receipt,
+		"buyer":       cols.buyer,
+		"deadline":    cols.deadline,
+		"name":        cols.name,
+		"phone":       cols.phone,
+		"province":    cols.province,
+		"city":        cols.city,
+		"district":    cols.district,
+		"postal_code": cols.postalCode,
+		"address":     cols.address,
+	}
+}
+
+// header mengembalikan nil kalau baris bukan header
+func (cols *exportColumns) header(row []string) columnIndex {
+	pos := map[string]int{}
+	for i, h := range row {
+		pos[normalizeHeader(h)] = i
+	}
+
+	index := columnIndex{}
+	for key, names := range cols.fields() {
+		for _, name := range names {
+			if i, ok := pos[normalizeHeader(name)]; ok {
+				index[key] = i
+				break
+			}
+		}
+	}
+
+	if _, ok := index["ref"]; !ok {
+		return nil
+	}
+	if _, ok := index["product"]; !ok {
+		return nil
+	}
+
+	return index
+}
+
+// parseAmount menerima format "Rp 150.000", "IDR 150,000", "150000.00"
+func parseAmount(s string) (int, error) {
+	clean := strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '.' || r == ',' || r == '-' {
+			return r
+		}
+		return -1
+	}, s)
+
+	if clean == "" {
+		return 0, nil
+	}
+
+	lastDot := strings.LastIndex(clean, ".")
+	lastComma := strings.LastIndex(clean, ",")
+
+	decimalSep := ""
+	switch {
+	case lastDot != -1 && lastComma != -1:
+		if lastDot > lastComma {
+			decimalSep = "."
+		} else {
+			decimalSep = ","
+		}
+	case lastDot != -1 && strings.Count(clean, ".") == 1 && len(clean)-lastDot-1 != 3:
+		decimalSep = "."
+	case lastComma != -1 && strings.Count(clean, ",") == 1 && len(clean)-lastComma-1 != 3:
+		decimalSep = ","
+	}
+
+	var intPart, fracPart string
+	if decimalSep != "" {
+		i := strings.LastIndex(clean, decimalSep)
+		intPart, fracPart = clean[:i], clean[i+1:]
+	} else {
+		intPart = clean
+	}
+
+	intPart = strings.NewReplacer(".", "", ",", "").Replace(intPart)
+	value, err := strconv.ParseFloat(intPart+"."+fracPart+"0", 64)
+	if err != nil {
+		return 0, fmt.Errorf("nominal %s tidak valid", s)
+	}
+
+	return int(math.Round(value)), nil
+}
+
+var timeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"02/01/2006 15:04:05",
+	"02/01/2006 15:04",
+	"02-01-2006 15:04:05",
+	"02-01-2006 15:04",
+	"02 Jan 2006 15:04",
+	"2006-01-02",
+	"02/01/2006",
+	"02-01-2006",
+}
+
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// parseTime waktu export dianggap waktu lokal seller
+func parseTime(s string, loc *time.Location) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range timeLayouts {
+		t, err := time.ParseInLocation(layout, s, loc)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	// xlsx menyimpan tanggal sebagai serial number
+	serial, err := strconv.ParseFloat(s, 64)
+	if err == nil && serial > 0 {
+		t := excelEpoch.Add(time.Duration(serial * float64(24*time.Hour)))
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc), nil
+	}
+
+	return time.Time{}, fmt.Errorf("waktu %s tidak valid", s)
+}
+
+type Importer struct {
+	mpType db_models.OrderMpType
+	cols   *exportColumns
+	teamID uint64
+	shopID uint64
+	loc    *time.Location
+}
+
+func NewImporter(mpType db_models.OrderMpType, teamID, shopID uint64, loc *time.Location) (*Importer, error) {
+	cols, ok := exportMaps[mpType]
+	if !ok {
+		return nil, importErr("marketplace %s belum didukung", mpType)
+	}
+
+	// waktu di file export tidak punya zona, tanpa zona dari request dibaca sebagai UTC
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	return &Importer{
+		mpType: mpType,
+		cols:   cols,
+		teamID: teamID,
+		shopID: shopID,
+		loc:    loc,
+	}, nil
+}
+
+// Parse baca file export, format dari ekstensi nama file (csv atau xlsx)
+func (imp *Importer) Parse(ctx context.Context, fileName string, data []byte) (*ImportResult, error) {
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".csv":
+		return imp.parseCSV(data)
+	case ".xlsx":
+		return imp.parseXLSX(ctx, data)
+	default:
+		return nil, importErr("format file %s tidak didukung", fileName)
+	}
+}
+
+func (imp *Importer) parseCSV(data []byte) (*ImportResult, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	rows := [][]string{}
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, importErr("csv tidak valid: %s", err)
+		}
+		rows = append(rows, row)
+	}
+
+	return imp.mapRows(rows)
+}
+
+func (imp *Importer) parseXLSX(ctx context.Context, data []byte) (*ImportResult, error) {
+	zreader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, importErr("xlsx tidak valid: %s", err)
+	}
+
+	workbook, err := excel_reader.NewExcelReader(zreader).GetWorkbook()
+	if err != nil {
+		return nil, importErr("xlsx tidak valid: %s", err)
+	}
+
+	names, err := sheetOrder(zreader, workbook)
+	if err != nil {
+		return nil, importErr("xlsx tidak valid: %s", err)
+	}
+
+	// sheet pertama (urutan di workbook) yang punya header dipakai
+	for _, name := range names {
+		sheet, err := workbook.GetSheet(name)
+		if err != nil {
+			return nil, err
+		}
+
+		rows := [][]string{}
+		err = sheet.Iterate(ctx, func(row []string) error {
+			rows = append(rows, row)
+			return nil
+		})
+		if err != nil {
+			return nil, importErr("xlsx tidak valid: %s", err)
+		}
+
+		result, err := imp.mapRows(rows)
+		if errors.Is(err, order_errors.ErrImportInvalid) {
+			continue
+		}
+		return result, err
+	}
+
+	return nil, importErr("header export %s tidak ditemukan", imp.mpType)
+}
+
+// sheetOrder urutan sheet sesuai tab di workbook.xml, map sheet di excel_reader tidak berurutan
+func sheetOrder(zreader *zip.Reader, workbook *excel_reader.Workbook) ([]string, error) {
+	file, err := zreader.Open("xl/workbook.xml")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var book struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"sheets>sheet"`
+	}
+
+	err = xml.NewDecoder(file).Decode(&book)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	seen := map[string]bool{}
+	for _, sheet := range book.Sheets {
+		if workbook.Sheets[sheet.Name] == nil || seen[sheet.Name] {
+			continue
+		}
+		seen[sheet.Name] = true
+		names = append(names, sheet.Name)
+	}
+
+	// sheet yang tidak tercatat di workbook.xml ditaruh paling akhir
+	rest := []string{}
+	for name := range workbook.Sheets {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+
+	return append(names, rest...), nil
+}
+
+func (imp *Importer) mapRows(rows [][]string) (*ImportResult, error) {
+	result := ImportResult{
+		Orders: []*ImportOrder{},
+		Errors: []*ImportRowError{},
+	}
+
+	// header dicari di beberapa baris awal
+	var index columnIndex
+	start := 0
+	for i := 0; i < len(rows) && i < 5; i++ {
+		index = imp.cols.header(rows[i])
+		if index != nil {
+			start = i + 1
+			break
+		}
+	}
+
+	if index == nil {
+		return nil, importErr("header export %s tidak ditemukan", imp.mpType)
+	}
+
+	if imp.cols.descRow {
+		start++
+	}
+
+	orderFrom, err := marketplaceType(imp.mpType)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := map[string]*ImportOrder{}
+	products := map[string]map[string]*order_iface.MpProductItem{}
+	failed := map[string]bool{}
+
+	for i := start; i < len(rows); i++ {
+		row := rows[i]
+		rowNum := i + 1
+
+		ref := index.get(row, "ref")
+		if ref == "" {
+			continue
+		}
+
+		if failed[ref] {
+			continue
+		}
+
+		rowErr := func(err error) {
+			failed[ref] = true
+			delete(orders, ref)
+			result.Errors = append(result.Errors, &ImportRowError{
+				Row:        rowNum,
+				OrderRefID: ref,
+				Message:    err.Error(),
+			})
+		}
+
+		count := 1
+		if !imp.cols.countPerItems {
+			count, err = strconv.Atoi(index.get(row, "count"))
+			if err != nil || count <= 0 {
+				rowErr(fmt.Errorf("jumlah %s tidak valid", index.get(row, "count")))
+				continue
+			}
+		}
+
+		total, err := parseAmount(index.get(row, "total"))
+		if err != nil {
+			rowErr(err)
+			continue
+		}
+
+		productName := index.get(row, "product")
+		if productName == "" {
+			rowErr(errors.New("nama produk kosong"))
+			continue
+		}
+		if variation := index.get(row, "variation"); variation != "" {
+			productName = productName + " - " + variation
+		}
+
+		ord := orders[ref]
+		if ord == nil {
+			orderTime, err := parseTime(index.get(row, "order_time"), imp.loc)
+			if err != nil {
+				rowErr(err)
+				continue
+			}
+
+			deadline, err := parseTime(index.get(row, "deadline"), imp.loc)
+			if err != nil {
+				rowErr(err)
+				continue
+			}
+
+			ord = &ImportOrder{
+				Row: rowNum,
+				Data: &order_iface.DraftOrderData{
+					OrderRefId:    ref,
+					OrderMpId:     imp.shopID,
+					OrderFrom:     orderFrom,
+					TeamId:        imp.teamID,
+					OrderTime:     timeOrNil(orderTime),
+					OrderDeadline: timeOrNil(deadline),
+					Receipt:       index.get(row, "receipt"),
+					BuyerUsername: index.get(row, "buyer"),
+					Address: &order_iface.DraftOrderAddress{
+						Name:       index.get(row, "name"),
+						Phone:      index.get(row, "phone"),
+						Province:   index.get(row, "province"),
+						City:       index.get(row, "city"),
+						District:   index.get(row, "district"),
+						PostalCode: index.get(row, "postal_code"),
+						Address:    index.get(row, "address"),
+					},
+					Items:     []*order_iface.OrderItem{},
+					BundleIds: []uint64{},
+				},
+				MpProducts: []*order_iface.MpProductItem{},
+			}
+			orders[ref] = ord
+			products[ref] = map[string]*order_iface.MpProductItem{}
+		}
+
+		// total order diulang di tiap baris, kecuali lazada yang per item
+		switch {
+		case imp.cols.totalPerItem:
+			ord.Data.OrderTotal += int64(total)
+		case ord.Data.OrderTotal == 0:
+			ord.Data.OrderTotal = int64(total)
+		}
+
+		item := products[ref][productName]
+		if item == nil {
+			item = &order_iface.MpProductItem{Name: productName}
+			products[ref][productName] = item
+			ord.MpProducts = append(ord.MpProducts, item)
+		}
+		item.Count += int64(count)
+	}
+
+	for _, ord := range orders {
+		result.Orders = append(result.Orders, ord)
+	}
+
+	sort.Slice(result.Orders, func(i, j int) bool {
+		return result.Orders[i].Row < result.Orders[j].Row
+	})
+
+	return &result, nil
+}
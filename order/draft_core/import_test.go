@@ -0,0 +1,141 @@
+package draft_core_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pdcgo/order_service/order/draft_core"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/schema/services/common/v1"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportExport(t *testing.T) {
+	t.Run("shopee beberapa produk satu order", func(t *testing.T) {
+		data := "No. Pesanan,Waktu Pesanan Dibuat,Nama Produk,Nama Variasi,Jumlah,Total Pembayaran,Username (Pembeli),Nama Penerima,Kota/Kabupaten\n" +
+			"250601AAA,2025-06-01 10:00,Kaos,Merah,2,Rp 150.000,budi,Budi,Bandung\n" +
+			"250601AAA,2025-06-01 10:00,Topi,,1,Rp 150.000,budi,Budi,Bandung\n" +
+			"250601BBB,2025-06-01 11:00,Kaos,Biru,x,\"Rp 50.000\",ani,Ani,Bogor\n" +
+			"250601CCC,2025-06-01 12:00,Kaos,Biru,1,\"Rp 75.500,50\",cici,Cici,Depok\n"
+
+		imp, err := draft_core.NewImporter(db_models.OrderMpShopee, 3, 8, time.UTC)
+		assert.Nil(t, err)
+
+		res, err := imp.Parse(context.Background(), "Order.all.20250601.csv", []byte(data))
+		assert.Nil(t, err)
+
+		assert.Len(t, res.Orders, 2)
+		assert.Len(t, res.Errors, 1)
+		assert.Equal(t, "250601BBB", res.Errors[0].OrderRefID)
+		assert.Equal(t, 4, res.Errors[0].Row)
+
+		ord := res.Orders[0]
+		assert.Equal(t, "250601AAA", ord.Data.OrderRefId)
+		assert.Equal(t, int64(150000), ord.Data.OrderTotal)
+		assert.Equal(t, uint64(8), ord.Data.OrderMpId)
+		assert.Equal(t, uint64(3), ord.Data.TeamId)
+		assert.Equal(t, common.MarketplaceType_MARKETPLACE_TYPE_SHOPEE, ord.Data.OrderFrom)
+		assert.Equal(t, "budi", ord.Data.BuyerUsername)
+		assert.Equal(t, "Bandung", ord.Data.Address.City)
+		assert.Equal(t, time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC), ord.Data.OrderTime.AsTime())
+		assert.Len(t, ord.MpProducts, 2)
+		assert.Equal(t, "Kaos - Merah", ord.MpProducts[0].Name)
+		assert.Equal(t, int64(2), ord.MpProducts[0].Count)
+
+		assert.Equal(t, int64(75501), res.Orders[1].Data.OrderTotal)
+	})
+
+	t.Run("lazada total dijumlah per item", func(t *testing.T) {
+		data := "orderNumber,createTime,itemName,paidPrice\n" +
+			"9001,01 Jun 2025 10:00,Kaos,50000.00\n" +
+			"9001,01 Jun 2025 10:00,Kaos,50000.00\n"
+
+		imp, err := draft_core.NewImporter(db_models.OrderMpLazada, 3, 9, time.UTC)
+		assert.Nil(t, err)
+
+		res, err := imp.Parse(context.Background(), "lazada.csv", []byte(data))
+		assert.Nil(t, err)
+		assert.Len(t, res.Orders, 1)
+		assert.Equal(t, int64(100000), res.Orders[0].Data.OrderTotal)
+		assert.Equal(t, int64(2), res.Orders[0].MpProducts[0].Count)
+	})
+
+	t.Run("xlsx pakai sheet pertama di workbook", func(t *testing.T) {
+		data := testXLSX(t,
+			[]string{"Zorder", "Aorder"},
+			[][][]string{
+				{
+					{"No. Pesanan", "Waktu Pesanan Dibuat", "Nama Produk", "Jumlah", "Total Pembayaran"},
+					{"250601ZZZ", "2025-06-01 10:00", "Kaos", "1", "50000"},
+				},
+				{
+					{"No. Pesanan", "Waktu Pesanan Dibuat", "Nama Produk", "Jumlah", "Total Pembayaran"},
+					{"250601AAA", "2025-06-01 10:00", "Kaos", "1", "50000"},
+				},
+			},
+		)
+
+		// tanpa zona waktu dibaca sebagai UTC
+		imp, err := draft_core.NewImporter(db_models.OrderMpShopee, 3, 8, nil)
+		assert.Nil(t, err)
+
+		res, err := imp.Parse(context.Background(), "Order.all.xlsx", data)
+		assert.Nil(t, err)
+		assert.Len(t, res.Orders, 1)
+		assert.Equal(t, "250601ZZZ", res.Orders[0].Data.OrderRefId)
+		assert.Equal(t, time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC), res.Orders[0].Data.OrderTime.AsTime())
+	})
+
+	t.Run("header tidak cocok", func(t *testing.T) {
+		imp, err := draft_core.NewImporter(db_models.OrderMpTiktok, 3, 9, time.UTC)
+		assert.Nil(t, err)
+
+		_, err = imp.Parse(context.Background(), "shopee.csv", []byte("No. Pesanan,Nama Produk\n1,Kaos\n"))
+		assert.ErrorIs(t, err, order_errors.ErrImportInvalid)
+
+		_, err = imp.Parse(context.Background(), "order.pdf", []byte{})
+		assert.ErrorIs(t, err, order_errors.ErrImportInvalid)
+	})
+}
+
+// testXLSX xlsx minimal, isi cell sebagai inline string
+func testXLSX(t *testing.T, names []string, sheets [][][]string) []byte {
+	buf := bytes.Buffer{}
+	zw := zip.NewWriter(&buf)
+
+	write := func(name string, content string) {
+		w, err := zw.Create(name)
+		assert.Nil(t, err)
+		_, err = w.Write([]byte(content))
+		assert.Nil(t, err)
+	}
+
+	book := `<workbook><sheets>`
+	for i, name := range names {
+		book += fmt.Sprintf(`<sheet name="%s" sheetId="%d"/>`, name, i+1)
+	}
+	book += `</sheets></workbook>`
+
+	for i, rows := range sheets {
+		sheet := `<worksheet><sheetData>`
+		for _, row := range rows {
+			sheet += `<row>`
+			for _, cell := range row {
+				sheet += fmt.Sprintf(`<c t="str"><v>%s</v></c>`, cell)
+			}
+			sheet += `</row>`
+		}
+		sheet += `</sheetData></worksheet>`
+		write(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), sheet)
+	}
+	write("xl/workbook.xml", book)
+
+	err := zw.Close()
+	assert.Nil(t, err)
+	return buf.Bytes()
+}
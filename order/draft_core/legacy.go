@@ -0,0 +1,326 @@
+package draft_core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/schema/services/common/v1"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/db_models"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// VersionProto versi payload yang sudah memakai DraftOrderData
+const VersionProto = "proto"
+
+// payload draft versi lama (sebelum proto), disimpan dari struct go biasa
+type LegacyDraftItem struct {
+	ProductID   uint `json:"product_id"`
+	VariationID uint `json:"variation_id"`
+	Count       int  `json:"count"`
+}
+
+type LegacyDraftAddress struct {
+	Name       string `json:"name"`
+	Phone      string `json:"phone"`
+	Province   string `json:"province"`
+	City       string `json:"city"`
+	District   string `json:"district"`
+	PostalCode string `json:"postal_code"`
+	Address    string `json:"address"`
+}
+
+type LegacyDraftPayload struct {
+	OrderRefID          string                `json:"order_ref_id"`
+	OrderMpID           uint                  `json:"order_mp_id"`
+	OrderFrom           db_models.OrderMpType `json:"order_from"`
+	OrderTime           time.Time             `json:"order_time"`
+	OrderTotal          int                   `json:"order_total"`
+	WarehouseID         uint                  `json:"warehouse_id"`
+	TeamID              uint                  `json:"team_id"`
+	ShippingID          uint                  `json:"shipping_id"`
+	ShipmentFee         float64               `json:"shipment_fee"`
+	ShipmentPaymentType string                `json:"shipment_payment_type"`
+	Receipt             string                `json:"receipt"`
+	ReceiptFile         string                `json:"receipt_file"`
+	Address             *LegacyDraftAddress   `json:"address"`
+	Items               []*LegacyDraftItem    `json:"items"`
+	BundleIDs           []uint                `json:"bundle_ids"`
+	OrderDeadline       time.Time             `json:"order_deadline"`
+	BuyerUsername       string                `json:"buyer_username"`
+}
+
+// DraftOrderRaw payload dibaca mentah, payload lama tidak bisa di scan ke DraftOrderData
+type DraftOrderRaw struct {
+	ID           uint                  `json:"id"`
+	TeamID       uint                  `json:"team_id"`
+	DraftVersion string                `json:"draft_version"`
+	OrderRefID   string                `json:"order_ref_id"`
+	OrderMpID    uint                  `json:"order_mp_id"`
+	OrderTotal   int                   `json:"order_total"`
+	OrderFrom    db_models.OrderMpType `json:"order_from"`
+	OrderPayload datatypes.JSON        `json:"order_payload"`
+}
+
+func (DraftOrderRaw) TableName() string {
+	return "draft_orders"
+}
+
+// DraftUpgradeFailure laporan draft yang gagal diupgrade
+type DraftUpgradeFailure struct {
+	DraftID     uint      `json:"draft_id" gorm:"primarykey;autoIncrement:false"`
+	TeamID      uint      `json:"team_id" gorm:"index"`
+	FromVersion string    `json:"from_version"`
+	Reason      string    `json:"reason"`
+	At          time.Time `json:"at"`
+}
+
+func upgradeErr(format string, args ...any) error {
+	return order_errors.DraftUpgrade(fmt.Sprintf(format, args...))
+}
+
+func marketplaceType(mpType db_models.OrderMpType) (common.MarketplaceType, error) {
+	value, ok := common.MarketplaceType_value["MARKETPLACE_TYPE_"+strings.ToUpper(string(mpType))]
+	if !ok || value == 0 {
+		return common.MarketplaceType_MARKETPLACE_TYPE_UNSPECIFIED, upgradeErr("marketplace %s tidak dikenal", mpType)
+	}
+	return common.MarketplaceType(value), nil
+}
+
+func timeOrNil(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// ConvertLegacy ubah payload lama ke DraftOrderData, field kosong diisi dari kolom draft
+func ConvertLegacy(draft *DraftOrderRaw) (*order_iface.DraftOrderData, error) {
+	if len(draft.OrderPayload) == 0 || string(draft.OrderPayload) == "null" {
+		return nil, upgradeErr("payload kosong")
+	}
+
+	var legacy LegacyDraftPayload
+	err := json.Unmarshal(draft.OrderPayload, &legacy)
+	if err != nil {
+		return nil, upgradeErr("payload tidak valid: %s", err)
+	}
+
+	if legacy.OrderRefID == "" {
+		legacy.OrderRefID = draft.OrderRefID
+	}
+	if legacy.OrderRefID == "" {
+		return nil, upgradeErr("order ref id kosong")
+	}
+	if legacy.OrderMpID == 0 {
+		legacy.OrderMpID = draft.OrderMpID
+	}
+	if legacy.OrderFrom == "" {
+		legacy.OrderFrom = draft.OrderFrom
+	}
+	if legacy.OrderTotal == 0 {
+		legacy.OrderTotal = draft.OrderTotal
+	}
+
+	orderFrom, err := marketplaceType(legacy.OrderFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	var paymentType order_iface.ShipmentPaymentType
+	if legacy.ShipmentPaymentType != "" {
+		value, ok := order_iface.ShipmentPaymentType_value["SHIPMENT_PAYMENT_TYPE_"+strings.ToUpper(legacy.ShipmentPaymentType)]
+		if !ok {
+			return nil, upgradeErr("shipment payment type %s tidak dikenal", legacy.ShipmentPaymentType)
+		}
+		paymentType = order_iface.ShipmentPaymentType(value)
+	}
+
+	data := &order_iface.DraftOrderData{
+		OrderRefId:          legacy.OrderRefID,
+		OrderMpId:           uint64(legacy.OrderMpID),
+		OrderFrom:           orderFrom,
+		OrderTime:           timeOrNil(legacy.OrderTime),
+		OrderTotal:          int64(legacy.OrderTotal),
+		WarehouseId:         uint64(legacy.WarehouseID),
+		TeamId:              uint64(draft.TeamID),
+		ShippingId:          uint64(legacy.ShippingID),
+		ShipmentFee:         legacy.ShipmentFee,
+		ShipmentPaymentType: paymentType,
+		Receipt:             legacy.Receipt,
+		ReceiptFile:         legacy.ReceiptFile,
+		DraftId:             uint64(draft.ID),
+		OrderDeadline:       timeOrNil(legacy.OrderDeadline),
+		BuyerUsername:       legacy.BuyerUsername,
+		Items:               []*order_iface.OrderItem{},
+		BundleIds:           []uint64{},
+	}
+
+	if legacy.Address != nil {
+		data.Address = &order_iface.DraftOrderAddress{
+			Name:       legacy.Address.Name,
+			Phone:      legacy.Address.Phone,
+			Province:   legacy.Address.Province,
+			City:       legacy.Address.City,
+			District:   legacy.Address.District,
+			PostalCode: legacy.Address.PostalCode,
+			Address:    legacy.Address.Address,
+		}
+	}
+
+	for i, item := range legacy.Items {
+		if item == nil || item.ProductID == 0 {
+			return nil, upgradeErr("item ke %d tanpa product id", i+1)
+		}
+
+		data.Items = append(data.Items, &order_iface.OrderItem{
+			ProductId:   uint64(item.ProductID),
+			VariationId: uint64(item.VariationID),
+			Count:       int64(item.Count),
+		})
+	}
+
+	for _, bundleID := range legacy.BundleIDs {
+		data.BundleIds = append(data.BundleIds, uint64(bundleID))
+	}
+
+	return data, nil
+}
+
+func upgradeRaw(tx *gorm.DB, draft *DraftOrderRaw, at time.Time) error {
+	data, convErr := ConvertLegacy(draft)
+	if convErr != nil {
+		err := tx.Save(&DraftUpgradeFailure{
+			DraftID:     draft.ID,
+			TeamID:      draft.TeamID,
+			FromVersion: draft.DraftVersion,
+			Reason:      convErr.Error(),
+			At:          at,
+		}).Error
+
+		if err != nil {
+			return err
+		}
+
+		return convErr
+	}
+
+	updates := map[string]interface{}{
+		"draft_version": VersionProto,
+		"order_payload": db_models.NewJSONType(data),
+	}
+
+	// kolom order_deadline dipakai reminder, ikut diisi dari payload hasil konversi
+	if data.OrderDeadline.IsValid() {
+		updates["order_deadline"] = data.OrderDeadline.AsTime()
+	}
+
+	return tx.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Model(&DraftOrderRaw{}).
+			Where("id = ?", draft.ID).
+			Where("team_id = ?", draft.TeamID).
+			Updates(updates).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		return tx.
+			Where("draft_id = ?", draft.ID).
+			Delete(&DraftUpgradeFailure{}).
+			Error
+	})
+}
+
+// Upgrade dipakai saat draft dibaca, draft yang sudah proto tidak diubah.
+// hanya draft milik team yang diupgrade, cek permission dilakukan sebelum memanggil ini.
+// error order_errors.ErrDraftUpgrade berarti payload tidak bisa dikonversi dan sudah dicatat.
+func Upgrade(tx *gorm.DB, teamID uint, draftID uint) error {
+	var draft DraftOrderRaw
+	err := tx.
+		Model(&DraftOrderRaw{}).
+		Where("id = ?", draftID).
+		Where("team_id = ?", teamID).
+		Find(&draft).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	if draft.ID == 0 || draft.DraftVersion == VersionProto {
+		return nil
+	}
+
+	return upgradeRaw(tx, &draft, time.Now())
+}
+
+type UpgradeReport struct {
+	Converted []uint
+	Failed    []*DraftUpgradeFailure
+}
+
+// UpgradeAll upgrade semua draft lama per batch. dry run hanya konversi tanpa menyimpan
+func UpgradeAll(ctx context.Context, db *gorm.DB, dryRun bool) (*UpgradeReport, error) {
+	report := UpgradeReport{
+		Converted: []uint{},
+		Failed:    []*DraftUpgradeFailure{},
+	}
+
+	now := time.Now()
+	var lastID uint
+	for {
+		drafts := []*DraftOrderRaw{}
+		err := db.
+			WithContext(ctx).
+			Model(&DraftOrderRaw{}).
+			Where("draft_version IS NULL OR draft_version != ?", VersionProto).
+			Where("id > ?", lastID).
+			Order("id asc").
+			Limit(500).
+			Find(&drafts).
+			Error
+
+		if err != nil {
+			return &report, err
+		}
+
+		if len(drafts) == 0 {
+			return &report, nil
+		}
+
+		for _, draft := range drafts {
+			lastID = draft.ID
+
+			if dryRun {
+				_, err = ConvertLegacy(draft)
+			} else {
+				err = upgradeRaw(db.WithContext(ctx), draft, now)
+			}
+
+			switch {
+			case err == nil:
+				report.Converted = append(report.Converted, draft.ID)
+			case errors.Is(err, order_errors.ErrDraftUpgrade):
+				report.Failed = append(report.Failed, &DraftUpgradeFailure{
+					DraftID:     draft.ID,
+					TeamID:      draft.TeamID,
+					FromVersion: draft.DraftVersion,
+					Reason:      err.Error(),
+					At:          now,
+				})
+			default:
+				return &report, err
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package draft_core_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pdcgo/order_service/order/draft_core"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/schema/services/common/v1"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/pkg/moretest"
+	"github.com/pdcgo/shared/pkg/moretest/moretest_mock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+func TestUpgradeLegacy(t *testing.T) {
+	var db gorm.DB
+
+	var migration moretest.SetupFunc = func(t *testing.T) func() error {
+		err := db.AutoMigrate(
+			&draft_core.DraftOrder{},
+			&draft_core.DraftUpgradeFailure{},
+		)
+		assert.Nil(t, err)
+		return nil
+	}
+
+	var seed moretest.SetupFunc = func(t *testing.T) func() error {
+		drafts := []*draft_core.DraftOrderRaw{
+			{
+				ID:         1,
+				TeamID:     3,
+				OrderRefID: "INV-1",
+				OrderMpID:  8,
+				OrderFrom:  "shopee",
+				OrderPayload: datatypes.JSON(`{
+					"order_time": "2025-06-01T10:00:00Z",
+					"order_deadline": "2025-06-03T10:00:00Z",
+					"shipment_payment_type": "buyer",
+					"address": {"name": "budi", "city": "bandung"},
+					"items": [{"product_id": 11, "variation_id": 12, "count": 2}],
+					"bundle_ids": [5]
+				}`),
+			},
+			{
+				ID:           2,
+				TeamID:       3,
+				OrderRefID:   "INV-2",
+				OrderFrom:    "blibli",
+				OrderPayload: datatypes.JSON(`{}`),
+			},
+			{
+				ID:           3,
+				TeamID:       3,
+				OrderRefID:   "INV-3",
+				OrderFrom:    "tiktok",
+				OrderPayload: datatypes.JSON(`{"items": [{"count": 1}]}`),
+			},
+		}
+
+		err := db.Save(&drafts).Error
+		assert.Nil(t, err)
+		return nil
+	}
+
+	moretest.Suite(t, "testing upgrade draft lama",
+		moretest.SetupListFunc{
+			moretest_mock.MockSqliteDatabase(&db),
+			migration,
+			seed,
+		},
+		func(t *testing.T) {
+			t.Run("dry run tidak menyimpan", func(t *testing.T) {
+				report, err := draft_core.UpgradeAll(context.Background(), &db, true)
+				assert.Nil(t, err)
+				assert.Equal(t, []uint{1}, report.Converted)
+				assert.Len(t, report.Failed, 2)
+
+				var count int64
+				err = db.Model(&draft_core.DraftUpgradeFailure{}).Count(&count).Error
+				assert.Nil(t, err)
+				assert.Equal(t, int64(0), count)
+			})
+
+			t.Run("upgrade saat dibaca", func(t *testing.T) {
+				// team lain tidak bisa mengubah draft
+				err := draft_core.Upgrade(&db, 4, 1)
+				assert.Nil(t, err)
+
+				var raw draft_core.DraftOrderRaw
+				err = db.First(&raw, 1).Error
+				assert.Nil(t, err)
+				assert.NotEqual(t, draft_core.VersionProto, raw.DraftVersion)
+
+				err = draft_core.Upgrade(&db, 3, 1)
+				assert.Nil(t, err)
+
+				var draft draft_core.DraftOrder
+				err = db.First(&draft, 1).Error
+				assert.Nil(t, err)
+				assert.Equal(t, draft_core.VersionProto, draft.DraftVersion)
+
+				data := draft.OrderPayload.Data()
+				assert.Equal(t, "INV-1", data.OrderRefId)
+				assert.Equal(t, uint64(8), data.OrderMpId)
+				assert.Equal(t, common.MarketplaceType_MARKETPLACE_TYPE_SHOPEE, data.OrderFrom)
+				assert.Equal(t, order_iface.ShipmentPaymentType_SHIPMENT_PAYMENT_TYPE_BUYER, data.ShipmentPaymentType)
+				assert.Equal(t, "bandung", data.Address.City)
+				assert.Equal(t, uint64(11), data.Items[0].ProductId)
+				assert.Equal(t, []uint64{5}, data.BundleIds)
+
+				deadline := time.Date(2025, 6, 3, 10, 0, 0, 0, time.UTC)
+				assert.True(t, data.OrderDeadline.AsTime().Equal(deadline))
+				if assert.NotNil(t, draft.OrderDeadline) {
+					assert.True(t, draft.OrderDeadline.Equal(deadline))
+				}
+			})
+
+			t.Run("gagal dicatat dengan alasan", func(t *testing.T) {
+				report, err := draft_core.UpgradeAll(context.Background(), &db, false)
+				assert.Nil(t, err)
+				assert.Len(t, report.Converted, 0)
+
+				failures := []*draft_core.DraftUpgradeFailure{}
+				err = db.Order("draft_id asc").Find(&failures).Error
+				assert.Nil(t, err)
+				assert.Len(t, failures, 2)
+				assert.Contains(t, failures[0].Reason, "marketplace blibli")
+				assert.Contains(t, failures[1].Reason, "tanpa product id")
+
+				err = draft_core.Upgrade(&db, 3, 2)
+				assert.ErrorIs(t, err, order_errors.ErrDraftUpgrade)
+			})
+		},
+	)
+}
@@ -0,0 +1,156 @@
+package draft_core
+
+import (
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// Migrate kolom baru di draft_orders, tabel dibuat di luar service ini
+// jadi hanya menambah kolom yang belum ada
+func Migrate(db *gorm.DB) error {
+	columns := map[string]string{
+		"version":           "Version",
+		"updated_by":        "UpdatedBy",
+		"updated":           "Updated",
+		"assignee_id":       "AssigneeID",
+		"is_valid":          "IsValid",
+		"validation_issues": "ValidationIssues",
+		"order_deadline":    "OrderDeadline",
+		"reminded_at":       "RemindedAt",
+	}
+
+	for column, field := range columns {
+		if db.Migrator().HasColumn(&DraftOrder{}, column) {
+			continue
+		}
+
+		err := db.Migrator().AddColumn(&DraftOrder{}, field)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, field := range []string{"AssigneeID", "OrderDeadline"} {
+		if db.Migrator().HasIndex(&DraftOrder{}, field) {
+			continue
+		}
+
+		err := db.Migrator().CreateIndex(&DraftOrder{}, field)
+		if err != nil {
+			return err
+		}
+	}
+
+	// draft lama belum punya assignee, diisi pembuatnya
+	err := db.
+		Model(&DraftOrder{}).
+		Where("assignee_id IS NULL OR assignee_id = 0").
+		Update("assignee_id", gorm.Expr("user_id")).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	// query json dan index trigram hanya untuk postgres
+	if db.Dialector.Name() != "postgres" {
+		return nil
+	}
+
+	err = backfillDeadline(db)
+	if err != nil {
+		return err
+	}
+
+	return migrateSearchIndex(db)
+}
+
+// deadline draft lama diambil dari payload. payload proto menyimpan timestamp sebagai seconds,
+// payload legacy menyimpan time.Time sebagai string RFC3339 (zero time berarti tidak ada deadline)
+func backfillDeadline(db *gorm.DB) error {
+	err := db.Exec(`
+		UPDATE draft_orders
+		SET order_deadline = to_timestamp((order_payload->'order_deadline'->>'seconds')::bigint)
+		WHERE order_deadline IS NULL
+		AND order_payload->'order_deadline'->>'seconds' IS NOT NULL
+	`).Error
+
+	if err != nil {
+		return err
+	}
+
+	return db.Exec(`
+		UPDATE draft_orders
+		SET order_deadline = (order_payload->>'order_deadline')::timestamptz
+		WHERE order_deadline IS NULL
+		AND draft_version IS DISTINCT FROM ?
+		AND jsonb_typeof(order_payload::jsonb->'order_deadline') = 'string'
+		AND order_payload->>'order_deadline' NOT LIKE '0001-01-01%'
+	`, VersionProto).Error
+}
+
+// index trigram untuk pencarian ILIKE '%q%' di OrderDraftList
+var searchIndexes = map[string]string{
+	"idx_draft_orders_team_created":   "(team_id, created DESC)",
+	"idx_draft_orders_ref_trgm":       "USING gin (order_ref_id gin_trgm_ops)",
+	"idx_draft_orders_receipt_trgm":   "USING gin ((order_payload->>'receipt') gin_trgm_ops)",
+	"idx_draft_orders_buyer_trgm":     "USING gin ((order_payload->>'buyer_username') gin_trgm_ops)",
+	"idx_draft_orders_recipient_trgm": "USING gin ((order_payload->'address'->>'name') gin_trgm_ops)",
+	"idx_draft_orders_phone_trgm":     "USING gin ((order_payload->'address'->>'phone') gin_trgm_ops)",
+	"idx_draft_orders_product_trgm":   "USING gin ((mp_products::text) gin_trgm_ops)",
+}
+
+// migrateSearchIndex hanya jalan kalau ada index yang belum ada atau invalid,
+// startup berikutnya cukup cek katalog. CONCURRENTLY supaya draft_orders tidak terkunci
+// saat index dibuat, jadi db tidak boleh di dalam transaction
+func migrateSearchIndex(db *gorm.DB) error {
+	names := make([]string, 0, len(searchIndexes))
+	for name := range searchIndexes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	missing := []string{}
+	for _, name := range names {
+		var valid int64
+		err := db.Raw(`
+			SELECT COUNT(*)
+			FROM pg_index i
+			JOIN pg_class c ON c.oid = i.indexrelid
+			WHERE c.relname = ? AND i.indisvalid
+		`, name).Scan(&valid).Error
+
+		if err != nil {
+			return err
+		}
+
+		if valid == 0 {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error
+	if err != nil {
+		return err
+	}
+
+	for _, name := range missing {
+		// index concurrently yang gagal di tengah tertinggal invalid, dibuat ulang
+		err = db.Exec("DROP INDEX CONCURRENTLY IF EXISTS " + name).Error
+		if err != nil {
+			return err
+		}
+
+		err = db.Exec("CREATE INDEX CONCURRENTLY " + name + " ON draft_orders " + searchIndexes[name]).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package draft_core
+
+import (
+	"time"
+
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/db_models"
+	"gorm.io/datatypes"
+)
+
+type DraftOrder struct { // bakalan ada di database
+	ID uint `json:"id"`
+
+	TeamID       uint   `json:"team_id"`
+	UserID       uint   `json:"user_id"`
+	AssigneeID   uint   `json:"assignee_id" gorm:"index"` // yang mengerjakan, awalnya sama dengan pembuat
+	DraftVersion string `json:"draft_version"`
+
+	OrderRefID   string                                          `json:"order_ref_id" gorm:"index"`
+	OrderMpID    uint                                            `json:"order_mp_id"`
+	OrderTotal   int                                             `json:"order_total"`
+	OrderFrom    db_models.OrderMpType                           `json:"order_from"`
+	OrderPayload db_models.JSONType[*order_iface.DraftOrderData] `json:"order_payload"`
+	MpProducts   datatypes.JSONSlice[*order_iface.MpProductItem] `json:"mp_products"`
+	Created      time.Time                                       `json:"created"`
+
+	// dipakai untuk menolak update dari tab lain yang datanya sudah basi
+	Version   int       `json:"version"`
+	UpdatedBy uint      `json:"updated_by"`
+	Updated   time.Time `json:"updated"`
+
+	// hasil validasi terakhir saat draft disimpan
+	IsValid          bool                                  `json:"is_valid"`
+	ValidationIssues datatypes.JSONSlice[*ValidationIssue] `json:"validation_issues"`
+
+	// salinan order_deadline payload supaya bisa diindex untuk reminder
+	OrderDeadline *time.Time `json:"order_deadline" gorm:"index"`
+	RemindedAt    *time.Time `json:"reminded_at"`
+
+	OrderMp *db_models.Marketplace `json:"order_mp"`
+	Team    *db_models.Team        `json:"team"`
+	User    *db_models.User        `json:"user"`
+}
+
+func (d *DraftOrder) GetEntityID() string {
+	return "draft_order"
+}
+
+// SyncDeadline isi kolom order_deadline dari payload, dipanggil setiap payload diubah
+func (d *DraftOrder) SyncDeadline() {
+	d.OrderDeadline = nil
+
+	data := d.OrderPayload.Data()
+	if data == nil || !data.OrderDeadline.IsValid() {
+		return
+	}
+
+	deadline := data.OrderDeadline.AsTime()
+	d.OrderDeadline = &deadline
+}
@@ -0,0 +1,159 @@
+package draft_core
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type DeadlineStatus string
+
+const (
+	DeadlineDueSoon DeadlineStatus = "due_soon"
+	DeadlineOverdue DeadlineStatus = "overdue"
+)
+
+type ReminderItem struct {
+	ID            uint           `json:"id"`
+	TeamID        uint           `json:"team_id"`
+	UserID        uint           `json:"user_id"`
+	AssigneeID    uint           `json:"assignee_id"`
+	OrderRefID    string         `json:"order_ref_id"`
+	OrderDeadline time.Time      `json:"order_deadline"`
+	RemindedAt    *time.Time     `json:"reminded_at"`
+	Status        DeadlineStatus `json:"status"`
+}
+
+// DraftReminderEvent event reminder per team, dibaca service notifikasi
+type DraftReminderEvent struct {
+	ID       uint                      `json:"id" gorm:"primarykey"`
+	TeamID   uint                      `json:"team_id" gorm:"index"`
+	DueSoon  int                       `json:"due_soon"`
+	Overdue  int                       `json:"overdue"`
+	DraftIDs datatypes.JSONSlice[uint] `json:"draft_ids"`
+	Created  time.Time                 `json:"created"`
+	SentAt   *time.Time                `json:"sent_at" gorm:"index"`
+}
+
+type ReminderFilter struct {
+	// 0 berarti semua team
+	TeamID uint
+	Within time.Duration
+	// hanya draft yang belum pernah diingatkan
+	OnlyNew bool
+}
+
+type Reminder struct {
+	db  *gorm.DB
+	now time.Time
+}
+
+func NewReminder(db *gorm.DB, now time.Time) *Reminder {
+	return &Reminder{
+		db:  db,
+		now: now,
+	}
+}
+
+// List draft yang deadline nya sudah lewat atau kurang dari filter.Within
+func (r *Reminder) List(ctx context.Context, filter *ReminderFilter) ([]*ReminderItem, error) {
+	items := []*ReminderItem{}
+
+	query := r.db.
+		WithContext(ctx).
+		Model(&DraftOrder{}).
+		Select([]string{
+			"id",
+			"team_id",
+			"user_id",
+			"assignee_id",
+			"order_ref_id",
+			"order_deadline",
+			"reminded_at",
+		}).
+		Where("order_deadline IS NOT NULL").
+		Where("order_deadline <= ?", r.now.Add(filter.Within)).
+		Order("order_deadline asc")
+
+	if filter.TeamID != 0 {
+		query = query.Where("team_id = ?", filter.TeamID)
+	}
+
+	if filter.OnlyNew {
+		query = query.Where("reminded_at IS NULL")
+	}
+
+	err := query.Find(&items).Error
+	if err != nil {
+		return items, err
+	}
+
+	for _, item := range items {
+		item.Status = DeadlineDueSoon
+		if item.OrderDeadline.Before(r.now) {
+			item.Status = DeadlineOverdue
+		}
+	}
+
+	return items, nil
+}
+
+// Remind tandai draft sudah diingatkan dan buat satu event per team
+func (r *Reminder) Remind(ctx context.Context, items []*ReminderItem) ([]*DraftReminderEvent, error) {
+	events := []*DraftReminderEvent{}
+	if len(items) == 0 {
+		return events, nil
+	}
+
+	teams := map[uint]*DraftReminderEvent{}
+	ids := make([]uint, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+
+		event := teams[item.TeamID]
+		if event == nil {
+			event = &DraftReminderEvent{
+				TeamID:   item.TeamID,
+				DraftIDs: datatypes.JSONSlice[uint]{},
+				Created:  r.now,
+			}
+			teams[item.TeamID] = event
+			events = append(events, event)
+		}
+
+		event.DraftIDs = append(event.DraftIDs, item.ID)
+		switch item.Status {
+		case DeadlineOverdue:
+			event.Overdue++
+		default:
+			event.DueSoon++
+		}
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Model(&DraftOrder{}).
+			Where("id IN ?", ids).
+			Update("reminded_at", r.now).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(&events).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		reminded := r.now
+		item.RemindedAt = &reminded
+	}
+
+	return events, nil
+}
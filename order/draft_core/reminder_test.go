@@ -0,0 +1,91 @@
+package draft_core_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pdcgo/order_service/order/draft_core"
+	"github.com/pdcgo/shared/pkg/moretest"
+	"github.com/pdcgo/shared/pkg/moretest/moretest_mock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestDraftReminder(t *testing.T) {
+	var db gorm.DB
+	now := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+
+	var migration moretest.SetupFunc = func(t *testing.T) func() error {
+		err := db.AutoMigrate(
+			&draft_core.DraftOrder{},
+			&draft_core.DraftReminderEvent{},
+		)
+		assert.Nil(t, err)
+		return nil
+	}
+
+	var seed moretest.SetupFunc = func(t *testing.T) func() error {
+		drafts := []*draft_core.DraftOrder{
+			{ID: 1, TeamID: 1, OrderRefID: "A1", OrderDeadline: at(-2 * time.Hour)},
+			{ID: 2, TeamID: 1, OrderRefID: "A2", OrderDeadline: at(5 * time.Hour)},
+			{ID: 3, TeamID: 1, OrderRefID: "A3", OrderDeadline: at(48 * time.Hour)},
+			{ID: 4, TeamID: 2, OrderRefID: "B1", OrderDeadline: at(time.Hour)},
+			{ID: 5, TeamID: 2, OrderRefID: "B2"},
+		}
+		err := db.Save(&drafts).Error
+		assert.Nil(t, err)
+		return nil
+	}
+
+	moretest.Suite(t, "testing draft reminder",
+		moretest.SetupListFunc{
+			moretest_mock.MockSqliteDatabase(&db),
+			migration,
+			seed,
+		},
+		func(t *testing.T) {
+			reminder := draft_core.NewReminder(&db, now)
+			filter := &draft_core.ReminderFilter{
+				Within:  24 * time.Hour,
+				OnlyNew: true,
+			}
+
+			t.Run("list deadline dekat dan lewat", func(t *testing.T) {
+				items, err := reminder.List(context.Background(), filter)
+				assert.Nil(t, err)
+				assert.Len(t, items, 3)
+				assert.Equal(t, uint(1), items[0].ID)
+				assert.Equal(t, draft_core.DeadlineOverdue, items[0].Status)
+				assert.Equal(t, draft_core.DeadlineDueSoon, items[1].Status)
+			})
+
+			t.Run("remind buat event per team", func(t *testing.T) {
+				items, err := reminder.List(context.Background(), filter)
+				assert.Nil(t, err)
+
+				events, err := reminder.Remind(context.Background(), items)
+				assert.Nil(t, err)
+				assert.Len(t, events, 2)
+				assert.Equal(t, uint(1), events[0].TeamID)
+				assert.Equal(t, 1, events[0].Overdue)
+				assert.Equal(t, 1, events[0].DueSoon)
+				assert.Equal(t, []uint{1, 2}, []uint(events[0].DraftIDs))
+
+				// sudah diingatkan tidak muncul lagi
+				items, err = reminder.List(context.Background(), filter)
+				assert.Nil(t, err)
+				assert.Empty(t, items)
+
+				var count int64
+				err = db.Model(&draft_core.DraftReminderEvent{}).Count(&count).Error
+				assert.Nil(t, err)
+				assert.Equal(t, int64(2), count)
+			})
+		},
+	)
+}
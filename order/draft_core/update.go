@@ -0,0 +1,207 @@
+package draft_core
+
+import (
+	"time"
+
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/db_models"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type UpdateDraft struct {
+	DraftID uint
+	TeamID  uint
+	Version int
+	UserID  uint
+	// kalau diisi hanya pembuat atau assignee yang boleh update
+	OwnerID    uint
+	Payload    *order_iface.DraftOrderData
+	MpProducts []*order_iface.MpProductItem
+}
+
+// Update patch payload dan mp products berdasarkan id. version harus sama dengan
+// yang tersimpan, created dan user_id pembuat tidak diubah
+func Update(tx *gorm.DB, upd *UpdateDraft) (*DraftOrder, error) {
+	// cek team dan owner tanpa membaca payload, draft lama belum bisa dibaca sebagai proto
+	var meta DraftOrder
+	err := tx.
+		Model(&DraftOrder{}).
+		Select("id", "team_id", "user_id", "assignee_id", "draft_version").
+		Where("id = ?", upd.DraftID).
+		Where("team_id = ?", upd.TeamID).
+		First(&meta).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	if upd.OwnerID != 0 && !meta.IsOwner(upd.OwnerID) {
+		return nil, order_errors.DraftNotOwned(uint64(meta.ID), uint64(upd.OwnerID))
+	}
+
+	// draft lama diupgrade dulu supaya payload yang ditimpa sudah proto
+	if meta.DraftVersion != VersionProto {
+		err = Upgrade(tx, upd.TeamID, meta.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var draft DraftOrder
+	err = tx.
+		Model(&DraftOrder{}).
+		Where("id = ?", meta.ID).
+		First(&draft).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	if draft.Version != upd.Version {
+		return nil, order_errors.DraftStale(uint64(draft.ID))
+	}
+
+	updates := map[string]interface{}{
+		"version":    draft.Version + 1,
+		"updated_by": upd.UserID,
+		"updated":    time.Now(),
+	}
+
+	if upd.Payload != nil {
+		payload := upd.Payload
+		payload.DraftId = uint64(draft.ID)
+		payload.TeamId = uint64(draft.TeamID)
+
+		updates["order_payload"] = db_models.NewJSONType(payload)
+		updates["draft_version"] = VersionProto
+		// reminder diulang kalau deadline berubah
+		previous := draft.OrderDeadline
+		draft.OrderPayload = db_models.NewJSONType(payload)
+		draft.SyncDeadline()
+		updates["order_deadline"] = draft.OrderDeadline
+		if previous == nil || draft.OrderDeadline == nil || !previous.Equal(*draft.OrderDeadline) {
+			updates["reminded_at"] = nil
+		}
+		updates["order_total"] = int(payload.OrderTotal)
+		if payload.OrderRefId != "" && payload.OrderRefId != draft.OrderRefID {
+			err = checkRefID(tx, &draft, payload.OrderRefId)
+			if err != nil {
+				return nil, err
+			}
+			updates["order_ref_id"] = payload.OrderRefId
+		}
+
+		// kolom marketplace ikut payload, dicek milik team seperti saat create
+		if payload.OrderMpId != 0 {
+			var shop db_models.Marketplace
+			err = tx.
+				Model(&db_models.Marketplace{}).
+				Select("id", "mp_type").
+				Where("team_id = ?", draft.TeamID).
+				Where("id = ?", payload.OrderMpId).
+				Find(&shop).
+				Error
+
+			if err != nil {
+				return nil, err
+			}
+
+			if shop.ID == 0 {
+				return nil, order_errors.ShopNotInTeam(payload.OrderMpId, uint64(draft.TeamID))
+			}
+
+			updates["order_mp_id"] = shop.ID
+			updates["order_from"] = db_models.OrderMpType(shop.MpType)
+		}
+	}
+
+	if upd.MpProducts != nil {
+		updates["mp_products"] = datatypes.NewJSONSlice(upd.MpProducts)
+	}
+
+	// version dicek lagi di where supaya update yang bersamaan tetap ditolak,
+	// draft lama yang version-nya masih null dianggap 0
+	res := tx.
+		Model(&DraftOrder{}).
+		Where("id = ?", draft.ID).
+		Where("COALESCE(version, 0) = ?", upd.Version).
+		Updates(updates)
+
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return nil, order_errors.DraftStale(uint64(draft.ID))
+	}
+
+	err = tx.
+		Model(&DraftOrder{}).
+		Where("id = ?", draft.ID).
+		First(&draft).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = NewValidator(tx).Apply(&draft)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.
+		Model(&DraftOrder{}).
+		Where("id = ?", draft.ID).
+		Updates(map[string]interface{}{
+			"is_valid":          draft.IsValid,
+			"validation_issues": draft.ValidationIssues,
+		}).
+		Error
+
+	return &draft, err
+}
+
+// checkRefID ref id baru tidak boleh dipakai draft lain atau order yang sudah ada di team
+func checkRefID(tx *gorm.DB, draft *DraftOrder, refID string) error {
+	var count int64
+	err := tx.
+		Model(&DraftOrder{}).
+		Where("team_id = ?", draft.TeamID).
+		Where("order_ref_id = ?", refID).
+		Where("id != ?", draft.ID).
+		Count(&count).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	if count != 0 {
+		return order_errors.DraftDuplicateRef(uint64(draft.ID), refID)
+	}
+
+	var ord db_models.Order
+	err = tx.
+		Model(&db_models.Order{}).
+		Select("id").
+		Where("team_id = ?", draft.TeamID).
+		Where("order_ref_id = ?", refID).
+		Where("status != ?", db_models.OrdCancel).
+		Find(&ord).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	if ord.ID != 0 {
+		return order_errors.DuplicateRefID(uint64(ord.ID), refID)
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package draft_core_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pdcgo/order_service/order/draft_core"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/pkg/moretest"
+	"github.com/pdcgo/shared/pkg/moretest/moretest_mock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+func TestUpdateDraft(t *testing.T) {
+	var db gorm.DB
+	created := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	var migration moretest.SetupFunc = func(t *testing.T) func() error {
+		err := db.AutoMigrate(
+			&draft_core.DraftOrder{},
+			&draft_core.DraftUpgradeFailure{},
+			&db_models.Order{},
+			&db_models.Marketplace{},
+		)
+		assert.Nil(t, err)
+		return nil
+	}
+
+	var seed moretest.SetupFunc = func(t *testing.T) func() error {
+		err := db.Save(&draft_core.DraftOrder{
+			ID:           1,
+			TeamID:       3,
+			UserID:       7,
+			OrderRefID:   "INV-1",
+			DraftVersion: draft_core.VersionProto,
+			Created:      created,
+		}).Error
+		assert.Nil(t, err)
+
+		// draft lama dengan payload legacy
+		err = db.Save(&draft_core.DraftOrderRaw{
+			ID:           2,
+			TeamID:       3,
+			OrderRefID:   "INV-2",
+			OrderFrom:    "shopee",
+			OrderPayload: datatypes.JSON(`{"items": [{"product_id": 11, "count": 1}]}`),
+		}).Error
+		assert.Nil(t, err)
+
+		err = db.Save(&db_models.Order{ID: 1, TeamID: 3, OrderRefID: "INV-3", Status: db_models.OrdCreated}).Error
+		assert.Nil(t, err)
+
+		err = db.Save(&db_models.Marketplace{ID: 8, TeamID: 3, MpUsername: "toko", MpType: "tiktok"}).Error
+		assert.Nil(t, err)
+		return nil
+	}
+
+	moretest.Suite(t, "testing update draft",
+		moretest.SetupListFunc{
+			moretest_mock.MockSqliteDatabase(&db),
+			migration,
+			seed,
+		},
+		func(t *testing.T) {
+			t.Run("update dengan version terbaru", func(t *testing.T) {
+				draft, err := draft_core.Update(&db, &draft_core.UpdateDraft{
+					DraftID: 1,
+					TeamID:  3,
+					Version: 0,
+					UserID:  9,
+					Payload: &order_iface.DraftOrderData{
+						OrderRefId: "INV-1",
+						OrderTotal: 120000,
+					},
+					MpProducts: []*order_iface.MpProductItem{
+						{Name: "Kaos", Count: 2},
+					},
+				})
+
+				assert.Nil(t, err)
+				assert.Equal(t, 1, draft.Version)
+				assert.Equal(t, uint(7), draft.UserID)
+				assert.Equal(t, uint(9), draft.UpdatedBy)
+				assert.True(t, draft.Created.Equal(created))
+				assert.Equal(t, 120000, draft.OrderTotal)
+				assert.Equal(t, uint64(1), draft.OrderPayload.Data().DraftId)
+				assert.Len(t, draft.MpProducts, 1)
+			})
+
+			t.Run("version basi ditolak", func(t *testing.T) {
+				_, err := draft_core.Update(&db, &draft_core.UpdateDraft{
+					DraftID: 1,
+					TeamID:  3,
+					Version: 0,
+					UserID:  10,
+				})
+				assert.ErrorIs(t, err, order_errors.ErrDraftStale)
+
+				var draft draft_core.DraftOrder
+				err = db.First(&draft, 1).Error
+				assert.Nil(t, err)
+				assert.Equal(t, uint(9), draft.UpdatedBy)
+			})
+
+			t.Run("ref id tidak boleh duplikat", func(t *testing.T) {
+				_, err := draft_core.Update(&db, &draft_core.UpdateDraft{
+					DraftID: 1,
+					TeamID:  3,
+					Version: 1,
+					Payload: &order_iface.DraftOrderData{OrderRefId: "INV-2"},
+				})
+				assert.ErrorIs(t, err, order_errors.ErrDraftDuplicateRef)
+
+				_, err = draft_core.Update(&db, &draft_core.UpdateDraft{
+					DraftID: 1,
+					TeamID:  3,
+					Version: 1,
+					Payload: &order_iface.DraftOrderData{OrderRefId: "INV-3"},
+				})
+				assert.ErrorIs(t, err, order_errors.ErrDuplicateRefID)
+			})
+
+			t.Run("draft lama ditimpa tetap bisa dibaca", func(t *testing.T) {
+				draft, err := draft_core.Update(&db, &draft_core.UpdateDraft{
+					DraftID: 2,
+					TeamID:  3,
+					Version: 0,
+					Payload: &order_iface.DraftOrderData{
+						OrderRefId: "INV-2",
+						OrderMpId:  8,
+					},
+				})
+				assert.Nil(t, err)
+				if !assert.NotNil(t, draft) {
+					return
+				}
+				assert.Equal(t, draft_core.VersionProto, draft.DraftVersion)
+				assert.Equal(t, uint(8), draft.OrderMpID)
+				assert.Equal(t, db_models.OrderMpType("tiktok"), draft.OrderFrom)
+
+				var saved draft_core.DraftOrder
+				err = db.First(&saved, 2).Error
+				assert.Nil(t, err)
+				assert.Equal(t, uint64(8), saved.OrderPayload.Data().OrderMpId)
+			})
+
+			t.Run("draft team lain", func(t *testing.T) {
+				_, err := draft_core.Update(&db, &draft_core.UpdateDraft{
+					DraftID: 1,
+					TeamID:  4,
+					Version: 1,
+				})
+				assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+			})
+		},
+	)
+}
@@ -0,0 +1,266 @@
+package draft_core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pdcgo/schema/services/common/v1"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/db_models"
+	"gorm.io/gorm"
+)
+
+type IssueSeverity string
+
+const (
+	// draft tidak bisa dijadikan order
+	SeverityError IssueSeverity = "error"
+	// masih bisa dijadikan order tapi perlu dicek seller
+	SeverityWarning IssueSeverity = "warning"
+)
+
+type ValidationIssue struct {
+	Field    string        `json:"field"`
+	Code     string        `json:"code"`
+	Message  string        `json:"message"`
+	Severity IssueSeverity `json:"severity"`
+}
+
+type ValidationResult struct {
+	Issues []*ValidationIssue
+}
+
+func (r *ValidationResult) add(severity IssueSeverity, field, code, format string, args ...any) {
+	r.Issues = append(r.Issues, &ValidationIssue{
+		Field:    field,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+		Severity: severity,
+	})
+}
+
+// Valid tidak ada issue dengan severity error
+func (r *ValidationResult) Valid() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			return false
+		}
+	}
+	return true
+}
+
+func mpTypeFromProto(mp common.MarketplaceType) db_models.OrderMpType {
+	name := strings.TrimPrefix(mp.String(), "MARKETPLACE_TYPE_")
+	return db_models.OrderMpType(strings.ToLower(name))
+}
+
+// field wajib per marketplace, order dari marketplace resi dari marketplace,
+// order manual (custom, mengantar) alamat dan kurir diisi seller
+func requiredFields(mpType db_models.OrderMpType, data *order_iface.DraftOrderData) map[string]bool {
+	address := data.Address
+	if address == nil {
+		address = &order_iface.DraftOrderAddress{}
+	}
+
+	switch mpType {
+	case db_models.OrderMpCustom, db_models.OrderMengantar:
+		return map[string]bool{
+			"shipping_id":     data.ShippingId != 0,
+			"address.name":    address.Name != "",
+			"address.phone":   address.Phone != "",
+			"address.address": address.Address != "",
+		}
+	default:
+		return map[string]bool{
+			"receipt": data.Receipt != "" || data.ReceiptFile != "",
+		}
+	}
+}
+
+type Validator struct {
+	tx *gorm.DB
+}
+
+func NewValidator(tx *gorm.DB) *Validator {
+	return &Validator{
+		tx: tx,
+	}
+}
+
+// Validate cek payload draft sebelum dijadikan order
+func (v *Validator) Validate(teamID uint, data *order_iface.DraftOrderData, mpProducts []*order_iface.MpProductItem) (*ValidationResult, error) {
+	result := ValidationResult{
+		Issues: []*ValidationIssue{},
+	}
+
+	if data == nil {
+		result.add(SeverityError, "payload", "required", "payload draft kosong")
+		return &result, nil
+	}
+
+	if data.OrderRefId == "" {
+		result.add(SeverityError, "order_ref_id", "required", "order ref id wajib diisi")
+	}
+	if data.WarehouseId == 0 {
+		result.add(SeverityError, "warehouse_id", "required", "gudang wajib dipilih")
+	}
+
+	// marketplace harus milik team dan tipenya sama dengan order_from
+	var shop db_models.Marketplace
+	err := v.tx.
+		Model(&db_models.Marketplace{}).
+		Where("id = ?", data.OrderMpId).
+		Where("team_id = ?", teamID).
+		Where("deleted = ?", false).
+		Find(&shop).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	mpType := mpTypeFromProto(data.OrderFrom)
+	switch {
+	case shop.ID == 0:
+		result.add(SeverityError, "order_mp_id", "not_found", "toko %d tidak ditemukan di team", data.OrderMpId)
+	case db_models.OrderMpType(shop.MpType) != mpType:
+		result.add(SeverityError, "order_from", "mismatch", "toko %s adalah %s, bukan %s", shop.MpName, shop.MpType, mpType)
+	}
+
+	if shop.ID != 0 {
+		mpType = db_models.OrderMpType(shop.MpType)
+	}
+
+	required := requiredFields(mpType, data)
+	fields := make([]string, 0, len(required))
+	for field := range required {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		if !required[field] {
+			result.add(SeverityError, field, "required", "%s wajib diisi untuk order %s", field, mpType)
+		}
+	}
+
+	err = v.validateItems(&result, teamID, data, mpProducts)
+	if err != nil {
+		return nil, err
+	}
+
+	// total order
+	switch {
+	case data.OrderTotal <= 0:
+		result.add(SeverityError, "order_total", "invalid", "total order harus lebih dari 0")
+	case data.ShipmentFee < 0:
+		result.add(SeverityError, "shipment_fee", "invalid", "ongkir tidak boleh minus")
+	case data.ShipmentFee > float64(data.OrderTotal):
+		result.add(SeverityError, "shipment_fee", "invalid", "ongkir %.0f lebih besar dari total order %d", data.ShipmentFee, data.OrderTotal)
+	}
+
+	return &result, nil
+}
+
+func (v *Validator) validateItems(result *ValidationResult, teamID uint, data *order_iface.DraftOrderData, mpProducts []*order_iface.MpProductItem) error {
+	if len(data.Items) == 0 && len(data.BundleIds) == 0 {
+		result.add(SeverityError, "items", "required", "produk belum dipilih")
+		return nil
+	}
+
+	productIDs := []uint64{}
+	variationIDs := []uint64{}
+	var itemCount int64
+	for i, item := range data.Items {
+		field := fmt.Sprintf("items[%d]", i)
+		if item.Count <= 0 {
+			result.add(SeverityError, field+".count", "invalid", "jumlah produk harus lebih dari 0")
+		}
+
+		itemCount += item.Count
+		productIDs = append(productIDs, item.ProductId)
+		if item.VariationId != 0 {
+			variationIDs = append(variationIDs, item.VariationId)
+		}
+	}
+
+	// sku harus ada dan milik team
+	products := map[uint64]bool{}
+	if len(productIDs) != 0 {
+		found := []uint64{}
+		err := v.tx.
+			Model(&db_models.Product{}).
+			Where("id IN ?", productIDs).
+			Where("team_id = ?", teamID).
+			Where("deleted = ?", false).
+			Pluck("id", &found).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		for _, id := range found {
+			products[id] = true
+		}
+	}
+
+	variations := map[uint64]uint64{}
+	if len(variationIDs) != 0 {
+		found := []*db_models.VariationValue{}
+		err := v.tx.
+			Model(&db_models.VariationValue{}).
+			Select("id", "product_id").
+			Where("id IN ?", variationIDs).
+			Find(&found).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		for _, variation := range found {
+			variations[uint64(variation.ID)] = uint64(variation.ProductID)
+		}
+	}
+
+	for i, item := range data.Items {
+		field := fmt.Sprintf("items[%d]", i)
+		if !products[item.ProductId] {
+			result.add(SeverityError, field+".product_id", "unknown_sku", "produk %d tidak ditemukan", item.ProductId)
+			continue
+		}
+
+		if item.VariationId != 0 && variations[item.VariationId] != item.ProductId {
+			result.add(SeverityError, field+".variation_id", "unknown_sku", "variasi %d tidak ditemukan di produk %d", item.VariationId, item.ProductId)
+		}
+	}
+
+	// bundle bisa berisi beberapa produk jadi jumlah tidak dibandingkan
+	if len(mpProducts) != 0 && len(data.BundleIds) == 0 {
+		var mpCount int64
+		for _, mp := range mpProducts {
+			mpCount += mp.Count
+		}
+
+		if mpCount != itemCount {
+			result.add(SeverityWarning, "items", "count_mismatch", "jumlah produk %d tidak sama dengan produk marketplace %d", itemCount, mpCount)
+		}
+	}
+
+	return nil
+}
+
+// Apply validasi saat draft disimpan, hasilnya disimpan di kolom draft
+// supaya list bisa menampilkan draft yang belum lengkap
+func (v *Validator) Apply(draft *DraftOrder) (*ValidationResult, error) {
+	result, err := v.Validate(draft.TeamID, draft.OrderPayload.Data(), draft.MpProducts)
+	if err != nil {
+		return nil, err
+	}
+
+	draft.IsValid = result.Valid()
+	draft.ValidationIssues = result.Issues
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package draft_core_test
+
+import (
+	"testing"
+
+	"github.com/pdcgo/order_service/order/draft_core"
+	"github.com/pdcgo/schema/services/common/v1"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/pkg/moretest"
+	"github.com/pdcgo/shared/pkg/moretest/moretest_mock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestValidateDraft(t *testing.T) {
+	var db gorm.DB
+
+	var migration moretest.SetupFunc = func(t *testing.T) func() error {
+		err := db.AutoMigrate(
+			&db_models.Marketplace{},
+			&db_models.Product{},
+			&db_models.VariationValue{},
+		)
+		assert.Nil(t, err)
+		return nil
+	}
+
+	var seed moretest.SetupFunc = func(t *testing.T) func() error {
+		err := db.Save(&db_models.Marketplace{ID: 8, TeamID: 3, MpType: db_models.MpShopee, MpName: "toko a", MpUsername: "tokoa"}).Error
+		assert.Nil(t, err)
+		err = db.Save(&db_models.Product{ID: 11, TeamID: 3, Name: "kaos"}).Error
+		assert.Nil(t, err)
+		err = db.Save(&db_models.VariationValue{ID: 12, ProductID: 11}).Error
+		assert.Nil(t, err)
+		return nil
+	}
+
+	issueCodes := func(issues []*draft_core.ValidationIssue) map[string]string {
+		codes := map[string]string{}
+		for _, issue := range issues {
+			codes[issue.Field] = issue.Code
+		}
+		return codes
+	}
+
+	moretest.Suite(t, "testing validate draft",
+		moretest.SetupListFunc{
+			moretest_mock.MockSqliteDatabase(&db),
+			migration,
+			seed,
+		},
+		func(t *testing.T) {
+			validator := draft_core.NewValidator(&db)
+
+			t.Run("draft lengkap", func(t *testing.T) {
+				res, err := validator.Validate(3, &order_iface.DraftOrderData{
+					OrderRefId:  "INV-1",
+					OrderMpId:   8,
+					OrderFrom:   common.MarketplaceType_MARKETPLACE_TYPE_SHOPEE,
+					OrderTotal:  100000,
+					ShipmentFee: 10000,
+					WarehouseId: 2,
+					Receipt:     "SPX01",
+					Items: []*order_iface.OrderItem{
+						{ProductId: 11, VariationId: 12, Count: 2},
+					},
+				}, []*order_iface.MpProductItem{{Name: "Kaos", Count: 2}})
+
+				assert.Nil(t, err)
+				assert.True(t, res.Valid())
+				assert.Empty(t, res.Issues)
+			})
+
+			t.Run("field kosong dan sku tidak dikenal", func(t *testing.T) {
+				res, err := validator.Validate(3, &order_iface.DraftOrderData{
+					OrderRefId:  "INV-2",
+					OrderMpId:   8,
+					OrderFrom:   common.MarketplaceType_MARKETPLACE_TYPE_TIKTOK,
+					OrderTotal:  5000,
+					ShipmentFee: 10000,
+					Items: []*order_iface.OrderItem{
+						{ProductId: 11, VariationId: 99, Count: 1},
+						{ProductId: 77, Count: 1},
+					},
+				}, []*order_iface.MpProductItem{{Name: "Kaos", Count: 3}})
+
+				assert.Nil(t, err)
+				assert.False(t, res.Valid())
+				assert.Equal(t, map[string]string{
+					"warehouse_id":          "required",
+					"order_from":            "mismatch",
+					"receipt":               "required",
+					"items[0].variation_id": "unknown_sku",
+					"items[1].product_id":   "unknown_sku",
+					"items":                 "count_mismatch",
+					"shipment_fee":          "invalid",
+				}, issueCodes(res.Issues))
+			})
+
+			t.Run("toko team lain", func(t *testing.T) {
+				res, err := validator.Validate(4, &order_iface.DraftOrderData{
+					OrderRefId: "INV-3",
+					OrderMpId:  8,
+					OrderFrom:  common.MarketplaceType_MARKETPLACE_TYPE_CUSTOM,
+				}, nil)
+
+				assert.Nil(t, err)
+				codes := issueCodes(res.Issues)
+				assert.Equal(t, "not_found", codes["order_mp_id"])
+				assert.Equal(t, "required", codes["address.phone"])
+				assert.Equal(t, "required", codes["items"])
+				assert.Equal(t, "invalid", codes["order_total"])
+			})
+		},
+	)
+}
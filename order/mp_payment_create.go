@@ -2,12 +2,12 @@ package order
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"math"
 
 	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/money"
 	"github.com/pdcgo/order_service/order/order_core"
+	"github.com/pdcgo/order_service/order_errors"
 	"github.com/pdcgo/schema/services/access_iface/v1"
 	"github.com/pdcgo/schema/services/order_iface/v1"
 	"github.com/pdcgo/schema/services/revenue_iface/v1"
@@ -15,6 +15,7 @@ import (
 	"github.com/pdcgo/shared/custom_connect"
 	"github.com/pdcgo/shared/db_models"
 	"github.com/pdcgo/shared/interfaces/authorization_iface"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"gorm.io/gorm"
 )
 
@@ -58,13 +59,15 @@ func (o *orderServiceImpl) MpPaymentCreate(ctx context.Context, req *connect.Req
 	result := order_iface.MpPaymentCreateResponse{}
 
 	if pay.Amount == 0 {
-		return &connect.Response[order_iface.MpPaymentCreateResponse]{}, errors.New("amount is zero")
+		return &connect.Response[order_iface.MpPaymentCreateResponse]{}, order_errors.ZeroAmount(pay.OrderId)
 	}
 
 	err = db.Transaction(func(tx *gorm.DB) error {
 
 		ordPayment := order_core.
-			NewOrderPaymentManage(tx, uint(pay.OrderId), agent.IdentityID(), pay)
+			NewOrderPaymentManage(tx, uint(pay.OrderId), agent.IdentityID(), pay).
+			SetCurrency(money.Currency(pay.Currency)).
+			SetAgent(agent.GetAgentType(), source.RequestFrom)
 
 		err = ordPayment.
 			Create()
@@ -78,63 +81,59 @@ func (o *orderServiceImpl) MpPaymentCreate(ctx context.Context, req *connect.Req
 		result.IsSendReceivableAdjustment = ordPayment.IsSendReceivableAdjustment
 		result.IsReceivableCreatedAdjustment = ordPayment.IsReceivableCreatedAdjustment
 
-		var desc string
-		if ordPayment.IsEdited {
-			desc = fmt.Sprintf("edit %s sebelumnya", ordPayment.Adj.Desc)
-		} else {
-			desc = pay.Desc
+		err = o.sendPaymentRevenue(ctx, ordPayment, pay)
+		if err != nil {
+			return err
 		}
 
-		if ordPayment.IsReceivableCreatedAdjustment {
-			if ordPayment.CreatedReceivableAdjustmentAmount != 0 {
-				// send to accounting revenue adjustment
-				_, err = o.revenueService.SellingReceivableAdjustment(ctx, &connect.Request[revenue_iface.SellingReceivableAdjustmentRequest]{
-					Msg: &revenue_iface.SellingReceivableAdjustmentRequest{
-						ShopId:   pay.ShopId,
-						OrderId:  uint64(ordPayment.Adj.OrderID),
-						AdjRefId: fmt.Sprintf("%s-%d", pay.Type, ordPayment.Adj.ID),
-						TeamId:   pay.TeamId,
-						Amount:   ordPayment.CreatedReceivableAdjustmentAmount,
-						Desc:     desc,
-						Type:     revenue_iface.ReceivableAdjustmentType_RECEIVABLE_ADJUSTMENT_TYPE_CREATED_REVENUE,
-						At:       pay.At,
-						WdAt:     pay.WdAt,
-					},
-				})
-
-				if err != nil {
-					return err
-				}
-			}
+		result.Id = uint64(ordPayment.Adj.ID)
+		return nil
+	})
 
-		}
+	return connect.NewResponse(&result), err
 
-		if ordPayment.IsSendReceivableAdjustment {
+}
 
-			revType, err := o.getType(ordPayment.Adj)
-			if err != nil {
-				return err
-			}
+// sendPaymentRevenue kirim adjustment ke accounting, dipakai mp payment dan order fund set
+// supaya efek ke ledger sama
+func (o *orderServiceImpl) sendPaymentRevenue(
+	ctx context.Context,
+	ordPayment *order_core.OrderPaymentManage,
+	pay *order_iface.MpPaymentCreateRequest,
+) error {
+	var err error
 
-			amount := ordPayment.Adj.Amount
-			switch revType {
-			case revenue_iface.ReceivableAdjustmentType_RECEIVABLE_ADJUSTMENT_TYPE_OTHER_COST,
-				revenue_iface.ReceivableAdjustmentType_RECEIVABLE_ADJUSTMENT_TYPE_OTHER_REVENUE:
-				amount = math.Abs(amount)
-			}
+	var desc string
+	if ordPayment.IsEdited {
+		desc = fmt.Sprintf("edit %s sebelumnya", ordPayment.Adj.Desc)
+	} else {
+		desc = pay.Desc
+	}
+
+	// order fund set tidak selalu mengirim at / wd at, pakai data adjustment
+	at := pay.At
+	if at == nil {
+		at = timestamppb.New(ordPayment.Adj.At)
+	}
+	wdAt := pay.WdAt
+	if wdAt == nil && !ordPayment.Adj.FundAt.IsZero() {
+		wdAt = timestamppb.New(ordPayment.Adj.FundAt)
+	}
 
+	if ordPayment.IsReceivableCreatedAdjustment {
+		if !ordPayment.CreatedReceivableAdjustmentAmount.IsZero() {
 			// send to accounting revenue adjustment
 			_, err = o.revenueService.SellingReceivableAdjustment(ctx, &connect.Request[revenue_iface.SellingReceivableAdjustmentRequest]{
 				Msg: &revenue_iface.SellingReceivableAdjustmentRequest{
 					ShopId:   pay.ShopId,
 					OrderId:  uint64(ordPayment.Adj.OrderID),
-					AdjRefId: fmt.Sprintf("%d", ordPayment.Adj.ID),
+					AdjRefId: fmt.Sprintf("%s-%d", pay.Type, ordPayment.Adj.ID),
 					TeamId:   pay.TeamId,
-					Amount:   amount,
+					Amount:   ordPayment.CreatedReceivableAdjustmentAmount.Float(),
 					Desc:     desc,
-					Type:     revType,
-					At:       pay.At,
-					WdAt:     pay.WdAt,
+					Type:     revenue_iface.ReceivableAdjustmentType_RECEIVABLE_ADJUSTMENT_TYPE_CREATED_REVENUE,
+					At:       at,
+					WdAt:     wdAt,
 				},
 			})
 
@@ -143,12 +142,43 @@ func (o *orderServiceImpl) MpPaymentCreate(ctx context.Context, req *connect.Req
 			}
 		}
 
-		result.Id = uint64(ordPayment.Adj.ID)
-		return nil
-	})
+	}
 
-	return connect.NewResponse(&result), err
+	if ordPayment.IsSendReceivableAdjustment {
+
+		revType, err := o.getType(ordPayment.Adj)
+		if err != nil {
+			return err
+		}
+
+		amount := money.FromFloat(ordPayment.Currency, ordPayment.Adj.Amount)
+		switch revType {
+		case revenue_iface.ReceivableAdjustmentType_RECEIVABLE_ADJUSTMENT_TYPE_OTHER_COST,
+			revenue_iface.ReceivableAdjustmentType_RECEIVABLE_ADJUSTMENT_TYPE_OTHER_REVENUE:
+			amount = amount.Abs()
+		}
+
+		// send to accounting revenue adjustment
+		_, err = o.revenueService.SellingReceivableAdjustment(ctx, &connect.Request[revenue_iface.SellingReceivableAdjustmentRequest]{
+			Msg: &revenue_iface.SellingReceivableAdjustmentRequest{
+				ShopId:   pay.ShopId,
+				OrderId:  uint64(ordPayment.Adj.OrderID),
+				AdjRefId: fmt.Sprintf("%d", ordPayment.Adj.ID),
+				TeamId:   pay.TeamId,
+				Amount:   amount.Float(),
+				Desc:     desc,
+				Type:     revType,
+				At:       at,
+				WdAt:     wdAt,
+			},
+		})
+
+		if err != nil {
+			return err
+		}
+	}
 
+	return nil
 }
 
 func (o *orderServiceImpl) getType(adj *db_models.OrderAdjustment) (revenue_iface.ReceivableAdjustmentType, error) {
@@ -2,107 +2,132 @@ package order
 
 import (
 	"context"
-	"errors"
+	"fmt"
 
 	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/order_core"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/schema/services/access_iface/v1"
 	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/schema/services/revenue_iface/v1"
+	"github.com/pdcgo/shared/custom_connect"
+	"github.com/pdcgo/shared/db_models"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // MpPaymentDelete implements order_ifaceconnect.OrderServiceHandler.
 func (o *orderServiceImpl) MpPaymentDelete(ctx context.Context, req *connect.Request[order_iface.MpPaymentDeleteRequest]) (*connect.Response[order_iface.MpPaymentDeleteResponse], error) {
-	// var err error
-
-	return nil, errors.New("unimplemented")
-
-	// source, err := custom_connect.GetRequestSource(ctx)
-	// if err != nil {
-	// 	return nil, err
-	// }
-
-	// pay := req.Msg
-
-	// var domainID uint
-	// switch source.RequestFrom {
-	// case access_iface.RequestFrom_REQUEST_FROM_ADMIN:
-	// 	domainID = authorization.RootDomain
-	// default:
-	// 	domainID = uint(pay.TeamId)
-	// }
-
-	// identity := o.auth.
-	// 	AuthIdentityFromHeader(req.Header())
-
-	// // agent := identity.
-	// // 	Identity()
-
-	// err = identity.
-	// 	HasPermission(authorization_iface.CheckPermissionGroup{
-	// 		&db_models.Order{}: &authorization_iface.CheckPermission{
-	// 			DomainID: domainID,
-	// 			Actions:  []authorization_iface.Action{authorization_iface.Update},
-	// 		},
-	// 	}).
-	// 	Err()
-
-	// if err != nil {
-	// 	return nil, err
-	// }
-
-	// db := o.db.WithContext(ctx)
-
-	// var adj db_models.OrderAdjustment
-
-	// err = order_core.NewChain(
-	// 	func(next order_core.NextFunc) order_core.NextFunc { // getting adjustment
-	// 		return func() error {
-	// 			err = db.
-	// 				Model(&db_models.OrderAdjustment{}).
-	// 				First(&adj, pay.AdjId).
-	// 				Error
-
-	// 			if err != nil {
-	// 				return err
-	// 			}
-
-	// 			return next()
-	// 		}
-	// 	},
-	// 	func(next order_core.NextFunc) order_core.NextFunc {
-	// 		return func() error {
-
-	// 			if domainID == authorization.RootDomain {
-	// 				return next()
-	// 			}
-
-	// 			var teamID uint64
-	// 			err = db.
-	// 				Model(&db_models.Order{}).
-	// 				Select("team_id").
-	// 				Where("id = ?", adj.OrderID).
-	// 				Find(&teamID).
-	// 				Error
-
-	// 			if err != nil {
-	// 				return err
-	// 			}
-
-	// 			if teamID != pay.TeamId {
-	// 				return err
-	// 			}
-
-	// 			return next()
-	// 		}
-	// 	},
-	// 	func(next order_core.NextFunc) order_core.NextFunc { // delete
-	// 		return func() error {
-	// 			err = db.Model(&db_models.OrderAdjustment{}).Where("id = ?", adj.ID).Delete(&db_models.OrderAdjustment{}).Error
-	// 			if err != nil {
-	// 				return err
-	// 			}
-	// 			return next()
-	// 		}
-	// 	},
-	// )
-
-	// return &connect.Response[order_iface.MpPaymentDeleteResponse]{}, err
+	var err error
+
+	source, err := custom_connect.GetRequestSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pay := req.Msg
+	actor, err := o.adjustmentActor(req.Header(), source, pay.TeamId)
+	if err != nil {
+		return nil, err
+	}
+
+	db := o.db.WithContext(ctx)
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var adj db_models.OrderAdjustment
+		err := tx.
+			Clauses(clause.Locking{
+				Strength: "UPDATE",
+			}).
+			Model(&db_models.OrderAdjustment{}).
+			Where("id = ?", pay.AdjId).
+			Where("deleted = ?", false).
+			Find(&adj).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		if adj.ID == 0 {
+			return order_errors.RecordNotFound(gorm.ErrRecordNotFound)
+		}
+
+		var teamID uint64
+		err = tx.
+			Model(&db_models.Order{}).
+			Select("team_id").
+			Where("id = ?", adj.OrderID).
+			Find(&teamID).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		if actor.RequestFrom != access_iface.RequestFrom_REQUEST_FROM_ADMIN && teamID != pay.TeamId {
+			return order_errors.WrongTeam(uint64(adj.OrderID), pay.TeamId)
+		}
+
+		currencies, err := order_core.GetAdjustmentCurrencies(tx, []uint{adj.ID})
+		if err != nil {
+			return err
+		}
+
+		// soft delete, lookup adjustment di order_core juga filter deleted
+		err = tx.
+			Model(&db_models.OrderAdjustment{}).
+			Where("id = ?", adj.ID).
+			Update("deleted", true).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		err = order_core.RecordAdjustmentLog(tx, actor, db_models.AdjLogDeleted, &adj, currencies[adj.ID])
+		if err != nil {
+			return err
+		}
+
+		// dikirim di dalam transaksi seperti mp payment create, gagal kirim berarti delete batal
+		return o.sendDeleteRevenue(ctx, &adj, teamID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&order_iface.MpPaymentDeleteResponse{}), nil
+}
+
+// sendDeleteRevenue nolkan adjustment yang sudah terkirim ke accounting,
+// ref id sama dengan saat create jadi nilai sebelumnya tertimpa
+func (o *orderServiceImpl) sendDeleteRevenue(ctx context.Context, adj *db_models.OrderAdjustment, teamID uint64) error {
+	revType, err := o.getType(adj)
+	if err != nil {
+		return err
+	}
+
+	var wdAt *timestamppb.Timestamp
+	if !adj.FundAt.IsZero() {
+		wdAt = timestamppb.New(adj.FundAt)
+	}
+
+	_, err = o.revenueService.SellingReceivableAdjustment(ctx, &connect.Request[revenue_iface.SellingReceivableAdjustmentRequest]{
+		Msg: &revenue_iface.SellingReceivableAdjustmentRequest{
+			ShopId:   uint64(adj.MpID),
+			OrderId:  uint64(adj.OrderID),
+			AdjRefId: fmt.Sprintf("%d", adj.ID),
+			TeamId:   teamID,
+			Amount:   0,
+			Desc:     fmt.Sprintf("hapus %s", adj.Desc),
+			Type:     revType,
+			At:       timestamppb.New(adj.At),
+			WdAt:     wdAt,
+		},
+	})
+
+	return err
 }
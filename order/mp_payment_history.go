@@ -0,0 +1,137 @@
+package order
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/money"
+	"github.com/pdcgo/order_service/order/order_core"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/schema/services/access_iface/v1"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/authorization"
+	"github.com/pdcgo/shared/custom_connect"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func adjustmentLogToProto(data *db_models.OrderAdjustment, currency money.Currency) *order_iface.PaymentOrderItem {
+	if data == nil {
+		return nil
+	}
+
+	return &order_iface.PaymentOrderItem{
+		Id:            uint64(data.ID),
+		OrderId:       uint64(data.OrderID),
+		IsMultiRegion: data.IsMultiRegion,
+		Type:          string(data.Type),
+		Amount:        data.Amount,
+		Currency:      string(currency.OrDefault()),
+		Desc:          data.Desc,
+		Source:        data.Source,
+		At:            timestamppb.New(data.At),
+		FundAt:        timestamppb.New(data.FundAt),
+	}
+}
+
+// MpPaymentHistory implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) MpPaymentHistory(ctx context.Context, req *connect.Request[order_iface.MpPaymentHistoryRequest]) (*connect.Response[order_iface.MpPaymentHistoryResponse], error) {
+	var err error
+
+	source, err := custom_connect.GetRequestSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pay := req.Msg
+
+	var domainID uint
+	switch source.RequestFrom {
+	case access_iface.RequestFrom_REQUEST_FROM_ADMIN:
+		domainID = authorization.RootDomain
+	default:
+		domainID = uint(pay.TeamId)
+	}
+
+	err = o.auth.
+		AuthIdentityFromHeader(req.Header()).
+		HasPermission(authorization_iface.CheckPermissionGroup{
+			&db_models.Order{}: &authorization_iface.CheckPermission{
+				DomainID: domainID,
+				Actions:  []authorization_iface.Action{authorization_iface.Read},
+			},
+		}).
+		Err()
+
+	if err != nil {
+		return nil, err
+	}
+
+	db := o.db.WithContext(ctx)
+	result := order_iface.MpPaymentHistoryResponse{
+		Items: []*order_iface.PaymentHistoryItem{},
+	}
+
+	histories, err := order_core.FindAdjustmentLogs(db, uint(pay.AdjId))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(histories) == 0 {
+		return connect.NewResponse(&result), nil
+	}
+
+	// check adjustment milik team
+	if domainID != authorization.RootDomain {
+		var teamID uint64
+		err = db.
+			Model(&db_models.Order{}).
+			Select("team_id").
+			Where("id = ?", histories[0].OrderID).
+			Find(&teamID).
+			Error
+
+		if err != nil {
+			return nil, err
+		}
+
+		if teamID != pay.TeamId {
+			// adjustment team lain dianggap tidak ada
+			return nil, order_errors.OrderNotFound(uint64(histories[0].OrderID), "")
+		}
+	}
+
+	// old diambil dari log sebelumnya, adjustment lama yang belum punya log created old-nya kosong
+	var prev *order_core.AdjustmentLog
+	for i, hist := range histories {
+		var oldItem, newItem *order_iface.PaymentOrderItem
+		switch hist.LogType {
+		case db_models.AdjLogDeleted:
+			oldItem = adjustmentLogToProto(hist.Data.Data(), hist.Currency)
+		default:
+			if prev != nil {
+				oldItem = adjustmentLogToProto(prev.Data.Data(), prev.Currency)
+			}
+			newItem = adjustmentLogToProto(hist.Data.Data(), hist.Currency)
+		}
+
+		result.Items = append(result.Items, &order_iface.PaymentHistoryItem{
+			Id:          uint64(hist.ID),
+			AdjId:       uint64(hist.AdjID),
+			OrderId:     uint64(hist.OrderID),
+			Version:     int64(i + 1),
+			Action:      string(hist.LogType),
+			UserId:      uint64(hist.UserID),
+			From:        string(hist.From),
+			RequestFrom: hist.RequestFrom,
+			Old:         oldItem,
+			New:         newItem,
+			Timestamp:   timestamppb.New(hist.Timestamp),
+		})
+
+		prev = hist
+	}
+
+	return connect.NewResponse(&result), nil
+}
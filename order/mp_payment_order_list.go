@@ -4,14 +4,35 @@ import (
 	"context"
 
 	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/money"
+	"github.com/pdcgo/order_service/order/order_core"
+	"github.com/pdcgo/schema/services/access_iface/v1"
+	"github.com/pdcgo/schema/services/common/v1"
 	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/authorization"
+	"github.com/pdcgo/shared/custom_connect"
+	"github.com/pdcgo/shared/db_connect"
 	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// limit list adjustment tanpa order id kalau page tidak dikirim
+const defaultPaymentPageLimit = 100
+
 type OrderAdjustmentList []*db_models.OrderAdjustment
 
-func (l OrderAdjustmentList) ToProto() []*order_iface.PaymentOrderItem {
+func (l OrderAdjustmentList) IDs() []uint {
+	ids := make([]uint, len(l))
+	for i, item := range l {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+func (l OrderAdjustmentList) ToProto(currencies map[uint]money.Currency) []*order_iface.PaymentOrderItem {
 	result := make([]*order_iface.PaymentOrderItem, len(l))
 	for i, item := range l {
 		result[i] = &order_iface.PaymentOrderItem{
@@ -21,6 +42,7 @@ func (l OrderAdjustmentList) ToProto() []*order_iface.PaymentOrderItem {
 			IsMultiRegion: item.IsMultiRegion,
 			Type:          string(item.Type),
 			Amount:        item.Amount,
+			Currency:      string(currencies[item.ID].OrDefault()),
 			Desc:          item.Desc,
 			Source:        item.Source,
 			At:            timestamppb.New(item.At),
@@ -33,27 +55,226 @@ func (l OrderAdjustmentList) ToProto() []*order_iface.PaymentOrderItem {
 // MpPaymentOrderList implements order_ifaceconnect.OrderServiceHandler.
 func (o *orderServiceImpl) MpPaymentOrderList(ctx context.Context, req *connect.Request[order_iface.MpPaymentOrderListRequest]) (*connect.Response[order_iface.MpPaymentOrderListResponse], error) {
 	var err error
+
+	source, err := custom_connect.GetRequestSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	pay := req.Msg
+
+	var domainID uint
+	switch source.RequestFrom {
+	case access_iface.RequestFrom_REQUEST_FROM_ADMIN:
+		domainID = authorization.RootDomain
+	default:
+		domainID = uint(pay.TeamId)
+	}
+
+	err = o.auth.
+		AuthIdentityFromHeader(req.Header()).
+		HasPermission(authorization_iface.CheckPermissionGroup{
+			&db_models.Order{}: &authorization_iface.CheckPermission{
+				DomainID: domainID,
+				Actions:  []authorization_iface.Action{authorization_iface.Read},
+			},
+		}).
+		Err()
+
+	if err != nil {
+		return nil, err
+	}
+
 	db := o.db.WithContext(ctx)
 
 	result := order_iface.MpPaymentOrderListResponse{
-		Items: []*order_iface.PaymentOrderItem{},
+		Items:    []*order_iface.PaymentOrderItem{},
+		Totals:   []*order_iface.PaymentTypeTotal{},
+		PageInfo: &common.PageInfo{},
+	}
+
+	// kolom waktu untuk filter dan sort
+	timeColumn := "a.at"
+	if pay.Sort != nil && pay.Sort.Field == order_iface.MpPaymentSortField_MP_PAYMENT_SORT_FIELD_FUND_AT {
+		timeColumn = "a.fund_at"
 	}
 
 	list := OrderAdjustmentList{}
 
-	err = db.
-		Model(&db_models.OrderAdjustment{}).
-		Where("order_id = ?", pay.OrderId).
-		Find(&list).
-		Error
+	_, err = db_connect.NewQueryChain(db,
+		func(db *gorm.DB, next db_connect.NextFunc) db_connect.NextFunc {
+			return func(query *gorm.DB) (*gorm.DB, error) { // selecting table
+				query = query.
+					Table("order_adjustments a").
+					Where("a.deleted = ?", false)
+
+				// non admin hanya boleh lihat adjustment order milik team
+				if domainID != authorization.RootDomain || pay.TeamId != 0 {
+					query = query.
+						Joins("JOIN orders o ON o.id = a.order_id").
+						Where("o.team_id = ?", pay.TeamId)
+				}
+
+				return next(query)
+			}
+		},
+		func(db *gorm.DB, next db_connect.NextFunc) db_connect.NextFunc {
+			return func(query *gorm.DB) (*gorm.DB, error) { // filter order dan shop
+				if pay.OrderId != 0 {
+					query = query.Where("a.order_id = ?", pay.OrderId)
+				}
+
+				if pay.ShopId != 0 {
+					query = query.Where("a.mp_id = ?", pay.ShopId)
+				}
+
+				return next(query)
+			}
+		},
+		func(db *gorm.DB, next db_connect.NextFunc) db_connect.NextFunc {
+			return func(query *gorm.DB) (*gorm.DB, error) { // filtering timerange
+				if pay.TimeRange == nil {
+					return next(query)
+				}
+
+				trange := pay.TimeRange
 
+				if trange.StartDate.IsValid() {
+					query = query.Where(timeColumn+" >= ?", trange.StartDate.AsTime())
+				}
+
+				if trange.EndDate.IsValid() {
+					query = query.Where(timeColumn+" <= ?", trange.EndDate.AsTime())
+				}
+
+				return next(query)
+			}
+		},
+		func(db *gorm.DB, next db_connect.NextFunc) db_connect.NextFunc {
+			return func(query *gorm.DB) (*gorm.DB, error) { // total per type, sebelum dipaginasi
+				totals := []struct {
+					Type     db_models.AdjustmentType
+					Currency money.Currency
+					Count    int64
+					Amount   float64
+				}{}
+
+				err := query.
+					Session(&gorm.Session{}).
+					Select([]string{
+						"a.type",
+						"a.currency",
+						"count(1) as count",
+						"sum(a.amount) as amount",
+					}).
+					Group("a.type, a.currency").
+					Order("a.type ASC").
+					Find(&totals).
+					Error
+
+				if err != nil {
+					return query, err
+				}
+
+				for _, total := range totals {
+					result.Totals = append(result.Totals, &order_iface.PaymentTypeTotal{
+						Type:     string(total.Type),
+						Currency: string(total.Currency.OrDefault()),
+						Count:    total.Count,
+						Amount:   money.FromFloat(total.Currency, total.Amount).Float(),
+					})
+				}
+
+				return next(query)
+			}
+		},
+		func(db *gorm.DB, next db_connect.NextFunc) db_connect.NextFunc {
+			return func(query *gorm.DB) (*gorm.DB, error) { // set pagination
+				// request lama per order tidak mengirim page, tetap kembalikan semua
+				// dengan page info satu halaman supaya client tahu datanya lengkap.
+				// list tanpa order id selalu dipaginasi supaya tidak mengambil semua adjustment
+				page := pay.Page
+				if page == nil {
+					if pay.OrderId != 0 {
+						var total int64
+						err := query.
+							Session(&gorm.Session{}).
+							Count(&total).
+							Error
+
+						if err != nil {
+							return query, err
+						}
+
+						result.PageInfo = &common.PageInfo{
+							CurrentPage: 1,
+							TotalPage:   1,
+							TotalItems:  total,
+						}
+
+						return next(query)
+					}
+
+					page = &common.PageFilter{
+						Page:  1,
+						Limit: defaultPaymentPageLimit,
+					}
+				}
+
+				var err error
+				var paginated *gorm.DB
+
+				paginated, result.PageInfo, err = db_connect.SetPaginationQuery(db, func() (*gorm.DB, error) {
+					return query.Session(&gorm.Session{}), nil
+				}, page)
+
+				if err != nil {
+					return query, err
+				}
+
+				return next(paginated)
+			}
+		},
+		func(db *gorm.DB, next db_connect.NextFunc) db_connect.NextFunc {
+			return func(query *gorm.DB) (*gorm.DB, error) { // sorting
+				desc := pay.Sort != nil && pay.Sort.Desc
+
+				query = query.Order(clause.OrderBy{
+					Columns: []clause.OrderByColumn{
+						{Column: clause.Column{Name: timeColumn, Raw: true}, Desc: desc},
+						{Column: clause.Column{Name: "a.id", Raw: true}, Desc: desc},
+					},
+				})
+
+				return next(query)
+			}
+		},
+		func(db *gorm.DB, next db_connect.NextFunc) db_connect.NextFunc {
+			return func(query *gorm.DB) (*gorm.DB, error) {
+				err := query.
+					Select("a.*").
+					Find(&list).
+					Error
+
+				if err != nil {
+					return query, err
+				}
+
+				return next(query)
+			}
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	currencies, err := order_core.GetAdjustmentCurrencies(db, list.IDs())
 	if err != nil {
 		return nil, err
 	}
 
-	result.Items = list.ToProto()
+	result.Items = list.ToProto(currencies)
 
 	return connect.NewResponse(&result), nil
-
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order_errors"
 	"github.com/pdcgo/schema/services/access_iface/v1"
 	"github.com/pdcgo/schema/services/order_iface/v1"
 	"github.com/pdcgo/shared/authorization"
@@ -98,7 +99,7 @@ func changeShipped(tx *gorm.DB, agent authorization_iface.Identity, change *orde
 	}
 
 	if !ord.IsGiveToCourrier() {
-		return fmt.Errorf("order %s is not given to courier", ord.OrderRefID)
+		return order_errors.IllegalStatus(uint64(ord.ID), ord.OrderRefID, string(ord.Status), string(db_models.OrdShipped))
 	}
 
 	// change status
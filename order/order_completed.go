@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/tag_rule"
+	"github.com/pdcgo/order_service/order_mutation"
 	"github.com/pdcgo/schema/services/access_iface/v1"
 	"github.com/pdcgo/schema/services/order_iface/v1"
 	"github.com/pdcgo/shared/authorization"
@@ -89,18 +91,17 @@ func (o *orderServiceImpl) OrderCompleted(ctx context.Context, req *connect.Requ
 		}
 
 		// removing tag related
-		err = tx.
-			Model(&db_models.OrderTagRelation{}).
-			Where("relation_from = ?", db_models.RelationFromTracking).
-			Where("order_id = ?", pay.OrderId).
-			Delete(&db_models.OrderTagRelation{}).
-			Error
+		err = order_mutation.
+			NewTagMutation(tx, order_mutation.WithActor(agent.IdentityID(), agent.GetAgentType())).
+			RemoveAllFrom(db_models.RelationFromTracking, []uint{uint(pay.OrderId)})
 
 		if err != nil {
 			return err
 		}
 
-		return nil
+		// tag rule dievaluasi ulang setelah status berubah
+		_, err = tag_rule.NewEngine(tx, ts.Timestamp).Evaluate([]uint{uint(pay.OrderId)})
+		return err
 
 	})
 
@@ -0,0 +1,149 @@
+package order_core
+
+import (
+	"time"
+
+	"github.com/pdcgo/order_service/money"
+	"github.com/pdcgo/schema/services/access_iface/v1"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/interfaces/identity_iface"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OrderAdjustmentLogSource kolom tambahan pada tabel order_adjustment_logs (model di shared)
+type OrderAdjustmentLogSource struct {
+	ID          uint                     `json:"id" gorm:"primarykey"`
+	AdjID       uint                     `json:"adj_id" gorm:"index"`
+	OrderID     uint                     `json:"order_id" gorm:"index"`
+	RequestFrom access_iface.RequestFrom `json:"request_from" gorm:"default:0"`
+	Currency    money.Currency           `json:"currency" gorm:"size:3;default:IDR"`
+}
+
+func (OrderAdjustmentLogSource) TableName() string {
+	return "order_adjustment_logs"
+}
+
+// AdjustmentLog log adjustment beserta kolom tambahannya
+type AdjustmentLog struct {
+	db_models.OrderAdjustmentLog
+	RequestFrom access_iface.RequestFrom `json:"request_from"`
+	Currency    money.Currency           `json:"currency"`
+}
+
+// MigrateAdjustmentLog tabel log milik shared, disini hanya menambah kolom dan index
+func MigrateAdjustmentLog(db *gorm.DB) error {
+	var err error
+	migrator := db.Migrator()
+
+	if !migrator.HasTable(&db_models.OrderAdjustmentLog{}) {
+		err = migrator.CreateTable(&db_models.OrderAdjustmentLog{})
+		if err != nil {
+			return err
+		}
+	}
+
+	model := &OrderAdjustmentLogSource{}
+	for _, field := range []string{"RequestFrom", "Currency"} {
+		if migrator.HasColumn(model, field) {
+			continue
+		}
+
+		err = migrator.AddColumn(model, field)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, field := range []string{"AdjID", "OrderID"} {
+		if migrator.HasIndex(model, field) {
+			continue
+		}
+
+		err = migrator.CreateIndex(model, field)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AdjustmentActor siapa dan dari mana perubahan adjustment dilakukan
+type AdjustmentActor struct {
+	UserID      uint
+	From        identity_iface.AgentType
+	RequestFrom access_iface.RequestFrom
+}
+
+// RecordAdjustmentLog append only, data isi adjustment setelah berubah,
+// untuk deleted isi terakhir sebelum dihapus
+func RecordAdjustmentLog(
+	tx *gorm.DB,
+	actor *AdjustmentActor,
+	logType db_models.OrderAdjLogType,
+	adj *db_models.OrderAdjustment,
+	currency money.Currency,
+) error {
+	// urutan log per adjustment dijaga dengan lock row adjustment
+	var lockedID uint
+	err := tx.
+		Clauses(clause.Locking{
+			Strength: "UPDATE",
+		}).
+		Model(&db_models.OrderAdjustment{}).
+		Select("id").
+		Where("id = ?", adj.ID).
+		Find(&lockedID).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	data := *adj
+	data.Order = nil
+	data.Mp = nil
+
+	log := db_models.OrderAdjustmentLog{
+		AdjID:     adj.ID,
+		OrderID:   adj.OrderID,
+		UserID:    actor.UserID,
+		From:      actor.From,
+		LogType:   logType,
+		Data:      datatypes.NewJSONType(&data),
+		Timestamp: time.Now(),
+	}
+
+	err = tx.
+		Omit(clause.Associations).
+		Save(&log).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	return tx.
+		Model(&OrderAdjustmentLogSource{}).
+		Where("id = ?", log.ID).
+		Updates(map[string]interface{}{
+			"request_from": actor.RequestFrom,
+			"currency":     currency.OrDefault(),
+		}).
+		Error
+}
+
+// FindAdjustmentLogs log satu adjustment urut dari yang paling lama
+func FindAdjustmentLogs(tx *gorm.DB, adjID uint) ([]*AdjustmentLog, error) {
+	logs := []*AdjustmentLog{}
+	err := tx.
+		Table("order_adjustment_logs").
+		Where("adj_id = ?", adjID).
+		Order("id asc").
+		Find(&logs).
+		Error
+
+	return logs, err
+}
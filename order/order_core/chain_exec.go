@@ -1,5 +1,7 @@
 package order_core
 
+import "gorm.io/gorm"
+
 type NextFunc func() error
 type NextHandler func(next NextFunc) NextFunc
 
@@ -21,3 +23,25 @@ func reverse[T any](s []T) {
 		s[i], s[j] = s[j], s[i]
 	}
 }
+
+// WithSavePoint menjalankan handler di dalam savepoint. kalau handler gagal
+// hanya perubahan handler yang dibatalkan, transaksi luar tetap bisa dipakai.
+// rowErr error dari handler, err error savepoint (transaksi sudah tidak bisa dipakai)
+func WithSavePoint(tx *gorm.DB, name string, handler func(tx *gorm.DB) error) (rowErr error, err error) {
+	err = tx.SavePoint(name).Error
+	if err != nil {
+		return nil, err
+	}
+
+	rowErr = handler(tx)
+	if rowErr != nil {
+		err = tx.RollbackTo(name).Error
+		if err != nil {
+			return rowErr, err
+		}
+	}
+
+	// release supaya savepoint tidak menumpuk untuk upload besar
+	err = tx.Exec("RELEASE SAVEPOINT " + name).Error
+	return rowErr, err
+}
@@ -0,0 +1,61 @@
+package order_core_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pdcgo/order_service/order/order_core"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/pkg/moretest"
+	"github.com/pdcgo/shared/pkg/moretest/moretest_mock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestWithSavePoint(t *testing.T) {
+	var db gorm.DB
+	var migration moretest.SetupFunc = func(t *testing.T) func() error {
+		err := db.AutoMigrate(&db_models.OrderTag{})
+		assert.Nil(t, err)
+		return nil
+	}
+
+	moretest.Suite(t, "testing savepoint per baris",
+		moretest.SetupListFunc{
+			moretest_mock.MockSqliteDatabase(&db),
+			migration,
+		},
+		func(t *testing.T) {
+			errRow := errors.New("baris gagal")
+
+			err := db.Transaction(func(tx *gorm.DB) error {
+				for _, name := range []string{"a", "b", "c"} {
+					rowErr, err := order_core.WithSavePoint(tx, "row", func(tx *gorm.DB) error {
+						err := tx.Save(&db_models.OrderTag{Name: name}).Error
+						if err != nil {
+							return err
+						}
+						if name == "b" {
+							return errRow
+						}
+						return nil
+					})
+					assert.Nil(t, err)
+
+					if name == "b" {
+						assert.ErrorIs(t, rowErr, errRow)
+					} else {
+						assert.Nil(t, rowErr)
+					}
+				}
+				return nil
+			})
+			assert.Nil(t, err)
+
+			names := []string{}
+			err = db.Model(&db_models.OrderTag{}).Order("name asc").Pluck("name", &names).Error
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"a", "c"}, names)
+		},
+	)
+}
@@ -0,0 +1,144 @@
+package order_core
+
+import (
+	"github.com/pdcgo/order_service/money"
+	"gorm.io/gorm"
+)
+
+// OrderCurrency kolom tambahan currency pada tabel orders (model di shared)
+type OrderCurrency struct {
+	ID       uint           `json:"id" gorm:"primarykey"`
+	Currency money.Currency `json:"currency" gorm:"size:3;default:IDR"`
+}
+
+func (OrderCurrency) TableName() string {
+	return "orders"
+}
+
+// OrderAdjustmentCurrency kolom tambahan currency pada tabel order_adjustments
+type OrderAdjustmentCurrency struct {
+	ID       uint           `json:"id" gorm:"primarykey"`
+	Currency money.Currency `json:"currency" gorm:"size:3;default:IDR"`
+}
+
+func (OrderAdjustmentCurrency) TableName() string {
+	return "order_adjustments"
+}
+
+// MarketplaceCurrency kolom tambahan currency pada tabel marketplaces,
+// jadi sumber currency order baru dari shop tersebut
+type MarketplaceCurrency struct {
+	ID       uint           `json:"id" gorm:"primarykey"`
+	Currency money.Currency `json:"currency" gorm:"size:3;default:IDR"`
+}
+
+func (MarketplaceCurrency) TableName() string {
+	return "marketplaces"
+}
+
+// MigrateCurrency hanya menambah kolom, AutoMigrate dengan model parsial
+// bisa membuat ulang tabel di beberapa dialect
+func MigrateCurrency(db *gorm.DB) error {
+	models := []interface{}{
+		&OrderCurrency{},
+		&OrderAdjustmentCurrency{},
+		&MarketplaceCurrency{},
+	}
+
+	for _, model := range models {
+		if db.Migrator().HasColumn(model, "currency") {
+			continue
+		}
+
+		err := db.Migrator().AddColumn(model, "Currency")
+		if err != nil {
+			return err
+		}
+	}
+
+	// insert orders dilakukan service lain, currency diisi trigger dari shop
+	if db.Dialector.Name() != "postgres" {
+		return nil
+	}
+
+	return migrateOrderCurrencyTrigger(db)
+}
+
+func migrateOrderCurrencyTrigger(db *gorm.DB) error {
+	err := db.Exec(`
+		CREATE OR REPLACE FUNCTION order_currency_from_shop() RETURNS trigger AS $$
+		DECLARE
+			shop_currency varchar(3);
+		BEGIN
+			SELECT m.currency INTO shop_currency
+			FROM marketplaces m
+			WHERE m.id = NEW.order_mp_id;
+
+			IF shop_currency IS NOT NULL THEN
+				NEW.currency := shop_currency;
+			END IF;
+
+			RETURN NEW;
+		END;
+		$$ LANGUAGE plpgsql
+	`).Error
+
+	if err != nil {
+		return err
+	}
+
+	err = db.Exec("DROP TRIGGER IF EXISTS orders_currency_from_shop ON orders").Error
+	if err != nil {
+		return err
+	}
+
+	return db.Exec(`
+		CREATE TRIGGER orders_currency_from_shop
+		BEFORE INSERT ON orders
+		FOR EACH ROW EXECUTE FUNCTION order_currency_from_shop()
+	`).Error
+}
+
+func GetOrderCurrency(tx *gorm.DB, orderID uint) (money.Currency, error) {
+	var ord OrderCurrency
+	err := tx.
+		Model(&OrderCurrency{}).
+		Select("id", "currency").
+		Where("id = ?", orderID).
+		Find(&ord).
+		Error
+
+	return ord.Currency.OrDefault(), err
+}
+
+func SetAdjustmentCurrency(tx *gorm.DB, adjID uint, currency money.Currency) error {
+	return tx.
+		Model(&OrderAdjustmentCurrency{}).
+		Where("id = ?", adjID).
+		Update("currency", currency.OrDefault()).
+		Error
+}
+
+func GetAdjustmentCurrencies(tx *gorm.DB, adjIDs []uint) (map[uint]money.Currency, error) {
+	result := map[uint]money.Currency{}
+	if len(adjIDs) == 0 {
+		return result, nil
+	}
+
+	items := []*OrderAdjustmentCurrency{}
+	err := tx.
+		Model(&OrderAdjustmentCurrency{}).
+		Where("id IN ?", adjIDs).
+		Find(&items).
+		Error
+
+	if err != nil {
+		return result, err
+	}
+
+	for _, item := range items {
+		result[item.ID] = item.Currency.OrDefault()
+	}
+
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package order_core
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pdcgo/order_service/order_errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"gorm.io/gorm"
+)
+
+func IsLockNotAvailable(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == order_errors.PgLockNotAvailable
+}
+
+var lockContention, _ = otel.
+	Meter("github.com/pdcgo/order_service/order/order_core").
+	Int64Counter(
+		"order.lock.contention",
+		metric.WithDescription("jumlah lock order yang gagal karena sedang dipakai transaksi lain"),
+	)
+
+type LockRetry struct {
+	Attempts  int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+var DefaultLockRetry = LockRetry{
+	Attempts:  4,
+	BaseDelay: 50 * time.Millisecond,
+	MaxDelay:  400 * time.Millisecond,
+}
+
+// Do menjalankan handler di savepoint, kalau lock tidak didapat diulang dengan jitter.
+// savepoint dibutuhkan karena di postgres error lock membatalkan transaksi.
+// kalau percobaan habis error lock terakhir dikembalikan.
+func (r LockRetry) Do(ctx context.Context, tx *gorm.DB, name string, handler func(tx *gorm.DB) error) error {
+	for attempt := 1; ; attempt++ {
+		rowErr, err := WithSavePoint(tx, name, handler)
+		if err != nil {
+			return err
+		}
+
+		if !IsLockNotAvailable(rowErr) {
+			if rowErr == nil && attempt > 1 {
+				lockContention.Add(ctx, 1, metric.WithAttributes(attribute.String("result", "acquired")))
+			}
+			return rowErr
+		}
+
+		if attempt >= r.Attempts {
+			lockContention.Add(ctx, 1, metric.WithAttributes(attribute.String("result", "exhausted")))
+			return rowErr
+		}
+
+		lockContention.Add(ctx, 1, metric.WithAttributes(attribute.String("result", "retry")))
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(rowErr, ctx.Err())
+		case <-time.After(r.delay(attempt)):
+		}
+	}
+}
+
+func (r LockRetry) delay(attempt int) time.Duration {
+	delay := r.BaseDelay << (attempt - 1)
+	if delay > r.MaxDelay || delay <= 0 {
+		delay = r.MaxDelay
+	}
+
+	// setengah tetap, setengah random supaya upload yang bentrok tidak retry bersamaan
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + rand.N(half)
+}
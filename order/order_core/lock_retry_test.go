@@ -0,0 +1,73 @@
+package order_core_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pdcgo/order_service/order/order_core"
+	"github.com/pdcgo/shared/pkg/moretest"
+	"github.com/pdcgo/shared/pkg/moretest/moretest_mock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestLockRetry(t *testing.T) {
+	var db gorm.DB
+
+	retry := order_core.LockRetry{
+		Attempts:  3,
+		BaseDelay: time.Millisecond,
+		MaxDelay:  2 * time.Millisecond,
+	}
+	lockErr := &pgconn.PgError{Code: "55P03", Message: "could not obtain lock on row in relation \"orders\""}
+
+	moretest.Suite(t, "testing retry lock nowait",
+		moretest.SetupListFunc{
+			moretest_mock.MockSqliteDatabase(&db),
+		},
+		func(t *testing.T) {
+			t.Run("lock didapat setelah retry", func(t *testing.T) {
+				attempt := 0
+				err := db.Transaction(func(tx *gorm.DB) error {
+					return retry.Do(context.Background(), tx, "order_lock", func(tx *gorm.DB) error {
+						attempt++
+						if attempt < 2 {
+							return lockErr
+						}
+						return nil
+					})
+				})
+				assert.Nil(t, err)
+				assert.Equal(t, 2, attempt)
+			})
+
+			t.Run("retry habis mengembalikan error lock", func(t *testing.T) {
+				attempt := 0
+				err := db.Transaction(func(tx *gorm.DB) error {
+					return retry.Do(context.Background(), tx, "order_lock", func(tx *gorm.DB) error {
+						attempt++
+						return lockErr
+					})
+				})
+				assert.True(t, order_core.IsLockNotAvailable(err))
+				assert.Equal(t, 3, attempt)
+			})
+
+			t.Run("error lain tidak diulang", func(t *testing.T) {
+				attempt := 0
+				errOther := errors.New("error lain")
+				err := db.Transaction(func(tx *gorm.DB) error {
+					return retry.Do(context.Background(), tx, "order_lock", func(tx *gorm.DB) error {
+						attempt++
+						return errOther
+					})
+				})
+				assert.ErrorIs(t, err, errOther)
+				assert.Equal(t, 1, attempt)
+			})
+		},
+	)
+}
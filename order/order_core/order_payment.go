@@ -2,9 +2,15 @@ package order_core
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/pdcgo/order_service/money"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/schema/services/access_iface/v1"
 	"github.com/pdcgo/schema/services/order_iface/v1"
 	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/interfaces/identity_iface"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -13,13 +19,18 @@ type OrderPaymentManage struct {
 	tx      *gorm.DB
 	orderID uint
 	userID  uint
+	actor   *AdjustmentActor
 
-	meta    *db_models.OrderPayment
-	pay     *order_iface.MpPaymentCreateRequest
-	mpTotal float64
+	meta     *db_models.OrderPayment
+	pay      *order_iface.MpPaymentCreateRequest
+	mpTotal  money.Amount
+	currency money.Currency
 
-	CreatedReceivableAdjustmentAmount float64
+	matchByType bool
+
+	CreatedReceivableAdjustmentAmount money.Amount
 	Adj                               *db_models.OrderAdjustment
+	Currency                          money.Currency
 	IsReceivableCreatedAdjustment     bool
 	IsEdited                          bool
 	IsSendReceivableAdjustment        bool
@@ -28,6 +39,7 @@ type OrderPaymentManage struct {
 func (o *OrderPaymentManage) Create(nexts ...NextHandler) error {
 	err := NewChain(
 		o.checkTeamID,
+		o.getCurrency,
 		o.getOrderPaymentMeta,
 		o.checkMustReceivableAdjusted,
 		o.createOrderAdjustment,
@@ -45,6 +57,11 @@ func (o *OrderPaymentManage) setOrderPaymentInfoLegacy(next NextFunc) NextFunc {
 			return next()
 		}
 
+		// order fund set belum tentu punya tanggal withdrawal
+		if o.pay.WdAt == nil {
+			return next()
+		}
+
 		err := o.
 			tx.
 			Model(&db_models.Order{}).
@@ -64,26 +81,63 @@ func (o *OrderPaymentManage) setOrderPaymentInfoLegacy(next NextFunc) NextFunc {
 	}
 }
 
+func (o *OrderPaymentManage) getCurrency(next NextFunc) NextFunc {
+	return func() error {
+		orderCurrency, err := GetOrderCurrency(o.tx, o.orderID)
+		if err != nil {
+			return err
+		}
+
+		o.Currency = o.currency
+		if o.Currency == "" {
+			o.Currency = orderCurrency
+		}
+
+		err = o.Currency.Validate()
+		if err != nil {
+			return err
+		}
+
+		return next()
+	}
+}
+
 func (o *OrderPaymentManage) getMpTotal(next NextFunc) NextFunc {
 	return func() error {
+		var ord struct {
+			OrderMpTotal float64
+			Currency     money.Currency
+		}
+
 		err := o.
 			tx.
-			Model(&db_models.Order{}).
+			Model(&OrderCurrency{}).
 			Where("id = ?", o.orderID).
-			Select("order_mp_total").
-			Find(&o.mpTotal).
+			Select("order_mp_total", "currency").
+			Find(&ord).
 			Error
 		if err != nil {
 			return err
 		}
 
+		o.mpTotal = money.FromFloat(ord.Currency, ord.OrderMpTotal)
 		return next()
 	}
 }
 
 func (o *OrderPaymentManage) calculateMpAdjustment(next NextFunc) NextFunc {
 	return func() error {
-		o.CreatedReceivableAdjustmentAmount = o.mpTotal - o.Adj.Amount
+		if !o.IsReceivableCreatedAdjustment {
+			return next()
+		}
+
+		// selisih est revenue hanya bisa dihitung jika currency sama dengan order
+		diff, err := o.mpTotal.Sub(money.FromFloat(o.Currency, o.Adj.Amount))
+		if err != nil {
+			return fmt.Errorf("order id %d adjustment %s: %w", o.orderID, o.Adj.Type, err)
+		}
+
+		o.CreatedReceivableAdjustmentAmount = diff
 		return next()
 	}
 }
@@ -93,13 +147,20 @@ func (o *OrderPaymentManage) createOrderAdjustment(next NextFunc) NextFunc {
 		var err error
 		var adj db_models.OrderAdjustment
 		pay := o.pay
+		amount := money.FromFloat(o.Currency, pay.Amount)
 
-		err = o.
+		query := o.
 			tx.
 			Model(&db_models.OrderAdjustment{}).
 			Where("order_id = ?", pay.OrderId).
-			Where("at = ?", pay.At.AsTime()).
 			Where("type = ?", pay.Type).
+			Where("deleted = ?", false)
+
+		if !o.matchByType {
+			query = query.Where("at = ?", pay.At.AsTime())
+		}
+
+		err = query.
 			Find(&adj).
 			Error
 
@@ -108,14 +169,15 @@ func (o *OrderPaymentManage) createOrderAdjustment(next NextFunc) NextFunc {
 		}
 
 		if adj.ID == 0 {
+			fundAt := timeOr(pay.WdAt, time.Time{})
 			adj = db_models.OrderAdjustment{
 				OrderID:       uint(pay.OrderId),
 				MpID:          uint(pay.ShopId),
 				IsMultiRegion: pay.IsMultiRegion,
-				At:            pay.At.AsTime(),
-				FundAt:        pay.WdAt.AsTime(),
+				At:            timeOr(pay.At, fundAt),
+				FundAt:        fundAt,
 				Type:          db_models.AdjustmentType(pay.Type),
-				Amount:        pay.Amount,
+				Amount:        amount.Float(),
 				Source:        pay.Source,
 				Desc:          pay.Desc,
 			}
@@ -129,21 +191,39 @@ func (o *OrderPaymentManage) createOrderAdjustment(next NextFunc) NextFunc {
 				return err
 			}
 
+			err = SetAdjustmentCurrency(o.tx, adj.ID, o.Currency)
+			if err != nil {
+				return err
+			}
+
+			err = RecordAdjustmentLog(o.tx, o.actor, db_models.AdjLogCreated, &adj, o.Currency)
+			if err != nil {
+				return err
+			}
+
 			o.IsSendReceivableAdjustment = true
 		} else {
-			if adj.Amount == pay.Amount &&
-				adj.FundAt.Equal(pay.WdAt.AsTime()) &&
-				adj.At.Equal(pay.At.AsTime()) {
+			at := timeOr(pay.At, adj.At)
+			fundAt := timeOr(pay.WdAt, adj.FundAt)
+
+			// dibandingkan dalam minor unit, bukan float
+			if money.FromFloat(o.Currency, adj.Amount).Equal(amount) &&
+				adj.FundAt.Equal(fundAt) &&
+				adj.At.Equal(at) {
 
 				o.Adj = &adj
 				return nil
 			}
 
-			adj.Amount = pay.Amount
-			adj.Desc = pay.Desc
-			adj.FundAt = pay.WdAt.AsTime()
-			adj.At = pay.At.AsTime()
-			adj.Source = pay.Source
+			adj.Amount = amount.Float()
+			adj.FundAt = fundAt
+			adj.At = at
+			if !o.matchByType || pay.Desc != "" {
+				adj.Desc = pay.Desc
+			}
+			if !o.matchByType || pay.Source != 0 {
+				adj.Source = pay.Source
+			}
 
 			err = o.tx.Save(&adj).Error
 
@@ -151,6 +231,16 @@ func (o *OrderPaymentManage) createOrderAdjustment(next NextFunc) NextFunc {
 				return err
 			}
 
+			err = SetAdjustmentCurrency(o.tx, adj.ID, o.Currency)
+			if err != nil {
+				return err
+			}
+
+			err = RecordAdjustmentLog(o.tx, o.actor, db_models.AdjLogUpdated, &adj, o.Currency)
+			if err != nil {
+				return err
+			}
+
 			o.IsSendReceivableAdjustment = true
 			o.IsEdited = true
 		}
@@ -177,7 +267,7 @@ func (o *OrderPaymentManage) checkTeamID(next NextFunc) NextFunc {
 
 		// checking team id
 		if teamID != o.pay.TeamId {
-			return fmt.Errorf("order id %d not in team id %d", o.pay.OrderId, o.pay.TeamId)
+			return order_errors.WrongTeam(o.pay.OrderId, o.pay.TeamId)
 		}
 
 		return next()
@@ -245,6 +335,26 @@ func (o *OrderPaymentManage) getOrderPaymentMeta(next NextFunc) NextFunc {
 	}
 }
 
+// SetCurrency currency adjustment, default mengikuti currency order
+func (o *OrderPaymentManage) SetCurrency(currency money.Currency) *OrderPaymentManage {
+	o.currency = currency
+	return o
+}
+
+// MatchByType dipakai order fund set: adjustment dicari per order dan type saja
+// (bukan per at), field yang kosong tidak menimpa data lama
+func (o *OrderPaymentManage) MatchByType() *OrderPaymentManage {
+	o.matchByType = true
+	return o
+}
+
+// SetAgent dicatat di history adjustment
+func (o *OrderPaymentManage) SetAgent(from identity_iface.AgentType, requestFrom access_iface.RequestFrom) *OrderPaymentManage {
+	o.actor.From = from
+	o.actor.RequestFrom = requestFrom
+	return o
+}
+
 func NewOrderPaymentManage(
 	tx *gorm.DB,
 	orderID,
@@ -255,6 +365,16 @@ func NewOrderPaymentManage(
 		tx:      tx,
 		orderID: orderID,
 		userID:  userID,
-		pay:     pay,
+		actor: &AdjustmentActor{
+			UserID: userID,
+		},
+		pay: pay,
+	}
+}
+
+func timeOr(ts *timestamppb.Timestamp, def time.Time) time.Time {
+	if ts == nil {
+		return def
 	}
+	return ts.AsTime()
 }
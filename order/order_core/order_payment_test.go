@@ -0,0 +1,227 @@
+package order_core_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pdcgo/order_service/money"
+	"github.com/pdcgo/order_service/order/order_core"
+	"github.com/pdcgo/schema/services/access_iface/v1"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/interfaces/identity_iface"
+	"github.com/pdcgo/shared/pkg/moretest"
+	"github.com/pdcgo/shared/pkg/moretest/moretest_mock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+func TestOrderPaymentManage(t *testing.T) {
+	var db gorm.DB
+	var migration moretest.SetupFunc = func(t *testing.T) func() error {
+		err := db.AutoMigrate(
+			&db_models.Order{},
+			&db_models.OrderPayment{},
+			&db_models.OrderAdjustment{},
+		)
+		assert.Nil(t, err)
+
+		err = order_core.MigrateCurrency(&db)
+		assert.Nil(t, err)
+
+		err = order_core.MigrateAdjustmentLog(&db)
+		assert.Nil(t, err)
+		return nil
+	}
+
+	var seed moretest.SetupFunc = func(t *testing.T) func() error {
+		orders := []*db_models.Order{
+			{
+				ID:           1,
+				TeamID:       1,
+				OrderMpTotal: 100000,
+			},
+			{
+				ID:           2,
+				TeamID:       1,
+				OrderMpTotal: 50,
+			},
+			{
+				ID:           3,
+				TeamID:       1,
+				OrderMpTotal: 20000,
+			},
+		}
+
+		err := db.Save(&orders).Error
+		assert.Nil(t, err)
+
+		err = db.Model(&order_core.OrderCurrency{}).Where("id = ?", 2).Update("currency", money.MYR).Error
+		assert.Nil(t, err)
+		return nil
+	}
+
+	at := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	moretest.Suite(t, "testing order payment",
+		moretest.SetupListFunc{
+			moretest_mock.MockSqliteDatabase(&db),
+			migration,
+			seed,
+		},
+		func(t *testing.T) {
+			t.Run("order fund dengan sisa float tidak membuat adjustment phantom", func(t *testing.T) {
+				pay := &order_iface.MpPaymentCreateRequest{
+					TeamId:  1,
+					OrderId: 1,
+					Type:    string(db_models.AdjOrderFund),
+					Amount:  99999.9999999,
+					At:      timestamppb.New(at),
+					WdAt:    timestamppb.New(at),
+				}
+
+				manage := order_core.NewOrderPaymentManage(&db, 1, 1, pay)
+				err := manage.Create()
+				assert.Nil(t, err)
+
+				assert.True(t, manage.IsReceivableCreatedAdjustment)
+				assert.True(t, manage.CreatedReceivableAdjustmentAmount.IsZero())
+				assert.Equal(t, float64(100000), manage.Adj.Amount)
+
+				t.Run("kirim ulang dengan noise float tidak dianggap edit", func(t *testing.T) {
+					pay.Amount = 100000.0000001
+					manage := order_core.NewOrderPaymentManage(&db, 1, 1, pay)
+					err := manage.Create()
+					assert.Nil(t, err)
+
+					assert.False(t, manage.IsEdited)
+					assert.False(t, manage.IsSendReceivableAdjustment)
+				})
+
+				t.Run("edit tercatat di history", func(t *testing.T) {
+					pay.Amount = 99000
+					pay.Desc = "koreksi"
+					manage := order_core.
+						NewOrderPaymentManage(&db, 1, 7, pay).
+						SetAgent(identity_iface.AgentType("user"), access_iface.RequestFrom_REQUEST_FROM_ADMIN)
+					err := manage.Create()
+					assert.Nil(t, err)
+					assert.True(t, manage.IsEdited)
+
+					histories, err := order_core.FindAdjustmentLogs(&db, manage.Adj.ID)
+					assert.Nil(t, err)
+					assert.Len(t, histories, 2)
+
+					assert.Equal(t, db_models.AdjLogCreated, histories[0].LogType)
+					assert.Equal(t, float64(100000), histories[0].Data.Data().Amount)
+
+					last := histories[1]
+					assert.Equal(t, db_models.AdjLogUpdated, last.LogType)
+					assert.Equal(t, uint(7), last.UserID)
+					assert.Equal(t, access_iface.RequestFrom_REQUEST_FROM_ADMIN, last.RequestFrom)
+					assert.Equal(t, money.IDR, last.Currency)
+					assert.Equal(t, float64(99000), last.Data.Data().Amount)
+					assert.Equal(t, "koreksi", last.Data.Data().Desc)
+				})
+
+				t.Run("adjustment yang sudah dihapus tidak dipakai ulang", func(t *testing.T) {
+					oldID := manage.Adj.ID
+					err := db.Model(&db_models.OrderAdjustment{}).Where("id = ?", oldID).Update("deleted", true).Error
+					assert.Nil(t, err)
+
+					manage := order_core.NewOrderPaymentManage(&db, 1, 1, pay)
+					err = manage.Create()
+					assert.Nil(t, err)
+
+					assert.NotEqual(t, oldID, manage.Adj.ID)
+					assert.True(t, manage.IsSendReceivableAdjustment)
+				})
+			})
+
+			t.Run("adjustment beda currency dengan order ditolak", func(t *testing.T) {
+				pay := &order_iface.MpPaymentCreateRequest{
+					TeamId:  1,
+					OrderId: 2,
+					Type:    string(db_models.AdjOrderFund),
+					Amount:  48.5,
+					At:      timestamppb.New(at),
+					WdAt:    timestamppb.New(at),
+				}
+
+				err := db.Transaction(func(tx *gorm.DB) error {
+					return order_core.
+						NewOrderPaymentManage(tx, 2, 1, pay).
+						SetCurrency(money.IDR).
+						Create()
+				})
+				assert.ErrorIs(t, err, money.ErrCurrencyMismatch)
+			})
+
+			t.Run("order fund set lalu completed set memakai adjustment yang sama", func(t *testing.T) {
+				fund := &order_iface.MpPaymentCreateRequest{
+					TeamId:  1,
+					OrderId: 3,
+					Type:    string(db_models.AdjOrderFund),
+					Amount:  19500,
+					At:      timestamppb.New(at),
+					Desc:    "fund set",
+				}
+
+				manage := order_core.NewOrderPaymentManage(&db, 3, 1, fund).MatchByType()
+				err := manage.Create()
+				assert.Nil(t, err)
+				assert.True(t, manage.IsReceivableCreatedAdjustment)
+				assert.Equal(t, int64(500), manage.CreatedReceivableAdjustmentAmount.Minor)
+				assert.True(t, manage.Adj.FundAt.IsZero())
+
+				var wdFund bool
+				err = db.Model(&db_models.Order{}).Select("wd_fund").Where("id = ?", 3).Find(&wdFund).Error
+				assert.Nil(t, err)
+				assert.False(t, wdFund)
+
+				wdAt := at.Add(48 * time.Hour)
+				completed := &order_iface.MpPaymentCreateRequest{
+					TeamId:  1,
+					OrderId: 3,
+					Type:    string(db_models.AdjOrderFund),
+					Amount:  19500,
+					WdAt:    timestamppb.New(wdAt),
+				}
+
+				manage = order_core.NewOrderPaymentManage(&db, 3, 1, completed).MatchByType()
+				err = manage.Create()
+				assert.Nil(t, err)
+				assert.True(t, manage.IsEdited)
+				assert.False(t, manage.IsReceivableCreatedAdjustment)
+				assert.True(t, manage.Adj.At.Equal(at))
+				assert.True(t, manage.Adj.FundAt.Equal(wdAt))
+				assert.Equal(t, "fund set", manage.Adj.Desc)
+
+				var count int64
+				err = db.Model(&db_models.OrderAdjustment{}).Where("order_id = ?", 3).Count(&count).Error
+				assert.Nil(t, err)
+				assert.Equal(t, int64(1), count)
+			})
+
+			t.Run("adjustment MYR mengikuti currency order", func(t *testing.T) {
+				pay := &order_iface.MpPaymentCreateRequest{
+					TeamId:  1,
+					OrderId: 2,
+					Type:    string(db_models.AdjOrderFund),
+					Amount:  48.555,
+					At:      timestamppb.New(at.Add(time.Hour)),
+					WdAt:    timestamppb.New(at),
+				}
+
+				manage := order_core.NewOrderPaymentManage(&db, 2, 1, pay)
+				err := manage.Create()
+				assert.Nil(t, err)
+
+				assert.Equal(t, money.MYR, manage.Currency)
+				assert.Equal(t, 48.56, manage.Adj.Amount)
+				assert.Equal(t, int64(144), manage.CreatedReceivableAdjustmentAmount.Minor)
+			})
+		},
+	)
+}
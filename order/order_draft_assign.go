@@ -0,0 +1,67 @@
+package order
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/draft_core"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+)
+
+// canManageDraft role team punya permission mengelola draft user lain
+func (o *orderServiceImpl) canManageDraft(agent authorization_iface.Identity, teamID uint64, action authorization_iface.Action) bool {
+	err := o.auth.HasPermission(agent, authorization_iface.CheckPermissionGroup{
+		&draft_core.DraftManage{}: &authorization_iface.CheckPermission{
+			DomainID: uint(teamID),
+			Actions:  []authorization_iface.Action{action},
+		},
+	})
+
+	return err == nil
+}
+
+// OrderDraftAssign implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) OrderDraftAssign(
+	ctx context.Context,
+	req *connect.Request[order_iface.OrderDraftAssignRequest],
+) (*connect.Response[order_iface.OrderDraftAssignResponse], error) {
+	var err error
+
+	res := order_iface.OrderDraftAssignResponse{}
+	pay := req.Msg
+
+	indentity := o.
+		auth.
+		AuthIdentityFromHeader(req.Header())
+
+	err = indentity.Err()
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	err = indentity.HasPermission(authorization_iface.CheckPermissionGroup{
+		&draft_core.DraftManage{}: &authorization_iface.CheckPermission{
+			DomainID: uint(pay.TeamId),
+			Actions:  []authorization_iface.Action{authorization_iface.Update},
+		},
+	}).Err()
+
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	if len(pay.DraftIds) == 0 {
+		return connect.NewResponse(&res), nil
+	}
+
+	draftIDs := make([]uint, len(pay.DraftIds))
+	for i, id := range pay.DraftIds {
+		draftIDs[i] = uint(id)
+	}
+
+	db := o.db.WithContext(ctx)
+	count, err := draft_core.Reassign(db, uint(pay.TeamId), draftIDs, uint(pay.AssigneeId))
+	res.Count = count
+	return connect.NewResponse(&res), err
+}
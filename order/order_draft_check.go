@@ -6,6 +6,7 @@ import (
 	"connectrpc.com/connect"
 	"github.com/pdcgo/schema/services/order_iface/v1"
 	"github.com/pdcgo/shared/db_models"
+	"gorm.io/gorm"
 )
 
 // OrderDraftCheck implements order_ifaceconnect.OrderServiceHandler.
@@ -13,22 +14,38 @@ func (o *orderServiceImpl) OrderDraftCheck(
 	ctx context.Context,
 	req *connect.Request[order_iface.OrderDraftCheckRequest],
 ) (*connect.Response[order_iface.OrderDraftCheckResponse], error) {
-	var err error
-
 	pay := req.Msg
 	db := o.db.WithContext(ctx)
 
+	data, err := draftCheck(db, pay.TeamId, pay.OrderRefIds)
+	if err != nil {
+		return nil, err
+	}
+
 	result := order_iface.OrderDraftCheckResponse{
-		Data: map[string]*order_iface.DraftCheckItem{},
+		Data: data,
 	}
 
-	for _, p := range pay.OrderRefIds {
-		result.Data[p] = &order_iface.DraftCheckItem{
+	return connect.NewResponse(&result), nil
+
+}
+
+// draftCheck cek ref id sudah ada di draft atau order team, dipakai juga saat import
+func draftCheck(db *gorm.DB, teamID uint64, refIDs []string) (map[string]*order_iface.DraftCheckItem, error) {
+	var err error
+
+	data := map[string]*order_iface.DraftCheckItem{}
+	for _, p := range refIDs {
+		data[p] = &order_iface.DraftCheckItem{
 			OrderRefId: p,
 			IsExist:    false,
 		}
 	}
 
+	if len(refIDs) == 0 {
+		return data, nil
+	}
+
 	draftList := []*DraftOrder{}
 	err = db.
 		Model(&DraftOrder{}).
@@ -37,17 +54,17 @@ func (o *orderServiceImpl) OrderDraftCheck(
 			"order_ref_id",
 			"team_id",
 		}).
-		Where("team_id = ?", pay.TeamId).
-		Where("order_ref_id IN ?", pay.OrderRefIds).
+		Where("team_id = ?", teamID).
+		Where("order_ref_id IN ?", refIDs).
 		Find(&draftList).
 		Error
 
 	if err != nil {
-		return nil, err
+		return data, err
 	}
 
 	for _, draft := range draftList {
-		result.Data[draft.OrderRefID].IsExist = true
+		data[draft.OrderRefID].IsExist = true
 	}
 
 	// getting from order
@@ -55,19 +72,18 @@ func (o *orderServiceImpl) OrderDraftCheck(
 	err = db.
 		Model(&db_models.Order{}).
 		Select([]string{"id", "order_ref_id"}).
-		Where("order_ref_id IN ?", pay.OrderRefIds).
-		Where("team_id = ?", pay.TeamId).
+		Where("order_ref_id IN ?", refIDs).
+		Where("team_id = ?", teamID).
 		Find(&ords).
 		Error
 
 	if err != nil {
-		return nil, err
+		return data, err
 	}
 
 	for _, ord := range ords {
-		result.Data[ord.OrderRefID].OrderIsExist = true
+		data[ord.OrderRefID].OrderIsExist = true
 	}
 
-	return connect.NewResponse(&result), nil
-
+	return data, nil
 }
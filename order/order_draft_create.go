@@ -2,15 +2,15 @@ package order
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/draft_core"
+	"github.com/pdcgo/order_service/order_errors"
 	"github.com/pdcgo/schema/services/order_iface/v1"
 	"github.com/pdcgo/shared/db_models"
 	"github.com/pdcgo/shared/interfaces/authorization_iface"
 	"google.golang.org/protobuf/types/known/timestamppb"
-	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -65,7 +65,7 @@ func (o *orderServiceImpl) OrderDraftCreate(
 	}
 
 	if ord.ID != 0 {
-		return connect.NewResponse(&res), fmt.Errorf("cannot create draft because order %s is exists", createPay.OrderRefId)
+		return connect.NewResponse(&res), order_errors.DuplicateRefID(uint64(ord.ID), createPay.OrderRefId)
 	}
 
 	err = db.Transaction(func(tx *gorm.DB) error {
@@ -86,8 +86,9 @@ func (o *orderServiceImpl) OrderDraftCreate(
 		draft := &DraftOrder{
 			TeamID:       uint(createPay.TeamId),
 			UserID:       agent.GetUserID(),
+			AssigneeID:   agent.GetUserID(),
 			OrderRefID:   createPay.OrderRefId,
-			DraftVersion: "proto",
+			DraftVersion: draft_core.VersionProto,
 			OrderMpID:    uint(createPay.OrderMpId),
 			OrderTotal:   int(createPay.OrderTotal),
 			OrderFrom:    db_models.OrderMpType(shop.MpType),
@@ -120,6 +121,12 @@ func (o *orderServiceImpl) OrderDraftCreate(
 
 		draft.OrderPayload = db_models.NewJSONType(createPay)
 
+		draft.SyncDeadline()
+		_, err = draft_core.NewValidator(tx).Apply(draft)
+		if err != nil {
+			return err
+		}
+
 		err = tx.Save(&draft).Error
 		if err != nil {
 			return err
@@ -138,26 +145,5 @@ type MpProductItem struct {
 	Count int    `json:"count"`
 }
 
-type DraftOrder struct { // bakalan ada di database
-	ID uint `json:"id"`
-
-	TeamID       uint   `json:"team_id"`
-	UserID       uint   `json:"user_id"`
-	DraftVersion string `json:"draft_version"`
-
-	OrderRefID   string                                          `json:"order_ref_id" gorm:"index"`
-	OrderMpID    uint                                            `json:"order_mp_id"`
-	OrderTotal   int                                             `json:"order_total"`
-	OrderFrom    db_models.OrderMpType                           `json:"order_from"`
-	OrderPayload db_models.JSONType[*order_iface.DraftOrderData] `json:"order_payload"`
-	MpProducts   datatypes.JSONSlice[*order_iface.MpProductItem] `json:"mp_products"`
-	Created      time.Time                                       `json:"created"`
-
-	OrderMp *db_models.Marketplace `json:"order_mp"`
-	Team    *db_models.Team        `json:"team"`
-	User    *db_models.User        `json:"user"`
-}
-
-func (d *DraftOrder) GetEntityID() string {
-	return "draft_order"
-}
+// DraftOrder model ada di draft_core supaya bisa dipakai batch cleanup
+type DraftOrder = draft_core.DraftOrder
@@ -0,0 +1,82 @@
+package order
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/draft_core"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// OrderDraftDeadlineList implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) OrderDraftDeadlineList(
+	ctx context.Context,
+	req *connect.Request[order_iface.OrderDraftDeadlineListRequest],
+) (*connect.Response[order_iface.OrderDraftDeadlineListResponse], error) {
+	var err error
+
+	res := order_iface.OrderDraftDeadlineListResponse{
+		Items: []*order_iface.DraftDeadlineItem{},
+	}
+	pay := req.Msg
+
+	indentity := o.
+		auth.
+		AuthIdentityFromHeader(req.Header())
+
+	err = indentity.Err()
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	actions := []authorization_iface.Action{authorization_iface.Read}
+	if pay.Flag {
+		actions = append(actions, authorization_iface.Update)
+	}
+
+	err = indentity.HasPermission(authorization_iface.CheckPermissionGroup{
+		&DraftOrder{}: &authorization_iface.CheckPermission{
+			DomainID: uint(pay.TeamId),
+			Actions:  actions,
+		},
+	}).Err()
+
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	reminder := draft_core.NewReminder(o.db, time.Now())
+	items, err := reminder.List(ctx, &draft_core.ReminderFilter{
+		TeamID:  uint(pay.TeamId),
+		Within:  time.Duration(pay.WithinHours) * time.Hour,
+		OnlyNew: pay.Flag,
+	})
+
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	if pay.Flag {
+		_, err = reminder.Remind(ctx, items)
+		if err != nil {
+			return connect.NewResponse(&res), err
+		}
+	}
+
+	for _, item := range items {
+		res.Items = append(res.Items, &order_iface.DraftDeadlineItem{
+			DraftId:       uint64(item.ID),
+			OrderRefId:    item.OrderRefID,
+			UserId:        uint64(item.UserID),
+			AssigneeId:    uint64(item.AssigneeID),
+			OrderDeadline: timestamppb.New(item.OrderDeadline),
+			Overdue:       item.Status == draft_core.DeadlineOverdue,
+			Reminded:      item.RemindedAt != nil,
+		})
+	}
+
+	return connect.NewResponse(&res), nil
+}
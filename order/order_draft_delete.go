@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/draft_core"
 	"github.com/pdcgo/schema/services/order_iface/v1"
 	"github.com/pdcgo/shared/interfaces/authorization_iface"
 )
@@ -42,11 +43,16 @@ func (o *orderServiceImpl) OrderDraftDelete(
 
 	db := o.db.WithContext(ctx)
 
-	err = db.
+	query := db.
 		Model(&DraftOrder{}).
 		Where("team_id = ?", pay.TeamId).
-		Where("user_id = ?", agent.GetUserID()).
-		Where("id = ?", pay.DraftId).
+		Where("id = ?", pay.DraftId)
+
+	if !o.canManageDraft(agent, pay.TeamId, authorization_iface.Delete) {
+		query = query.Scopes(draft_core.OwnerScope(agent.GetUserID()))
+	}
+
+	err = query.
 		Delete(&DraftOrder{}).
 		Error
 
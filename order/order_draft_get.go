@@ -2,9 +2,9 @@ package order
 
 import (
 	"context"
-	"errors"
 
 	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/draft_core"
 	"github.com/pdcgo/schema/services/order_iface/v1"
 	"github.com/pdcgo/shared/interfaces/authorization_iface"
 )
@@ -44,10 +44,18 @@ func (o *orderServiceImpl) OrderDraftGet(
 	}
 
 	db := o.db.WithContext(ctx)
+
+	// draft versi lama diupgrade saat dibuka
+	err = draft_core.Upgrade(db, uint(pay.TeamId), uint(pay.Id))
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
 	var draft DraftOrder
 	err = db.
 		Model(&DraftOrder{}).
 		Where("id = ?", pay.Id).
+		Where("team_id = ?", pay.TeamId).
 		First(&draft).
 		Error
 
@@ -55,15 +63,18 @@ func (o *orderServiceImpl) OrderDraftGet(
 		return connect.NewResponse(&res), err
 	}
 
-	if draft.DraftVersion != "proto" {
-		return connect.NewResponse(&res), errors.New("draft order is older version")
-	}
-
 	res.Data = &order_iface.DraftItem{
 		Id:         uint64(draft.ID),
 		TeamId:     uint64(draft.TeamID),
+		UserId:     uint64(draft.UserID),
+		AssigneeId: uint64(draft.AssigneeID),
 		MpProducts: draft.MpProducts,
 		Payload:    draft.OrderPayload.Data(),
+		Version:    int64(draft.Version),
+		UpdatedBy:  uint64(draft.UpdatedBy),
+		Updated:    timestampOrNil(draft.Updated),
+		IsValid:    draft.IsValid,
+		Issues:     validationIssuesToProto(draft.ValidationIssues),
 	}
 
 	return connect.NewResponse(&res), err
@@ -0,0 +1,159 @@
+package order
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/draft_core"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+	"gorm.io/gorm"
+)
+
+// batas ukuran file export
+const draftImportMaxSize = 10 << 20
+
+// OrderDraftImport implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) OrderDraftImport(
+	ctx context.Context,
+	req *connect.Request[order_iface.OrderDraftImportRequest],
+) (*connect.Response[order_iface.OrderDraftImportResponse], error) {
+	var err error
+
+	res := order_iface.OrderDraftImportResponse{
+		DraftIds:      []uint64{},
+		SkippedRefIds: []string{},
+		Errors:        []*order_iface.DraftImportRowError{},
+	}
+	pay := req.Msg
+
+	indentity := o.
+		auth.
+		AuthIdentityFromHeader(req.Header())
+
+	agent := indentity.Identity()
+
+	err = indentity.Err()
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	err = indentity.HasPermission(authorization_iface.CheckPermissionGroup{
+		&db_models.Order{}: &authorization_iface.CheckPermission{
+			DomainID: uint(pay.TeamId),
+			Actions:  []authorization_iface.Action{authorization_iface.Create},
+		},
+	}).Err()
+
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	if len(pay.Data) > draftImportMaxSize {
+		return connect.NewResponse(&res), order_errors.ImportTooLarge(draftImportMaxSize >> 20)
+	}
+
+	db := o.db.WithContext(ctx)
+
+	// getting marketplace
+	var shop db_models.Marketplace
+	err = db.
+		Model(&db_models.Marketplace{}).
+		Where("team_id = ?", pay.TeamId).
+		Where("id = ?", pay.ShopId).
+		First(&shop).
+		Error
+
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	// zona waktu file export dari request, kosong berarti UTC
+	var loc *time.Location
+	if pay.Timezone != "" {
+		loc, err = time.LoadLocation(pay.Timezone)
+		if err != nil {
+			return connect.NewResponse(&res), order_errors.InvalidTimezone(pay.Timezone)
+		}
+	}
+
+	importer, err := draft_core.NewImporter(db_models.OrderMpType(shop.MpType), pay.TeamId, pay.ShopId, loc)
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	parsed, err := importer.Parse(ctx, pay.FileName, pay.Data)
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	for _, rowErr := range parsed.Errors {
+		res.Errors = append(res.Errors, &order_iface.DraftImportRowError{
+			Row:        int64(rowErr.Row),
+			OrderRefId: rowErr.OrderRefID,
+			Message:    rowErr.Message,
+		})
+	}
+
+	refIDs := make([]string, len(parsed.Orders))
+	for i, ord := range parsed.Orders {
+		refIDs[i] = ord.Data.OrderRefId
+	}
+
+	// ref yang sudah ada draft atau order tidak dibuat ulang
+	checks, err := draftCheck(db, pay.TeamId, refIDs)
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, ord := range parsed.Orders {
+			check := checks[ord.Data.OrderRefId]
+			if check.IsExist || check.OrderIsExist {
+				res.SkippedRefIds = append(res.SkippedRefIds, ord.Data.OrderRefId)
+				continue
+			}
+
+			draft := &DraftOrder{
+				TeamID:       uint(pay.TeamId),
+				UserID:       agent.GetUserID(),
+				AssigneeID:   agent.GetUserID(),
+				OrderRefID:   ord.Data.OrderRefId,
+				DraftVersion: draft_core.VersionProto,
+				OrderMpID:    uint(pay.ShopId),
+				OrderTotal:   int(ord.Data.OrderTotal),
+				OrderFrom:    db_models.OrderMpType(shop.MpType),
+				Created:      time.Now(),
+				MpProducts:   ord.MpProducts,
+			}
+
+			err := tx.Save(&draft).Error
+			if err != nil {
+				return err
+			}
+
+			ord.Data.DraftId = uint64(draft.ID)
+			draft.OrderPayload = db_models.NewJSONType(ord.Data)
+
+			draft.SyncDeadline()
+			_, err = draft_core.NewValidator(tx).Apply(draft)
+			if err != nil {
+				return err
+			}
+
+			err = tx.Save(&draft).Error
+			if err != nil {
+				return err
+			}
+
+			res.DraftIds = append(res.DraftIds, uint64(draft.ID))
+		}
+
+		return nil
+	})
+
+	return connect.NewResponse(&res), err
+}
@@ -2,8 +2,10 @@ package order
 
 import (
 	"context"
+	"strings"
 
 	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/draft_core"
 	"github.com/pdcgo/schema/services/access_iface/v1"
 	"github.com/pdcgo/schema/services/common/v1"
 	"github.com/pdcgo/schema/services/order_iface/v1"
@@ -12,6 +14,7 @@ import (
 	"github.com/pdcgo/shared/interfaces/authorization_iface"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // OrderDraftList implements order_ifaceconnect.OrderServiceHandler.
@@ -79,6 +82,10 @@ func (o *orderServiceImpl) OrderDraftList(
 					query = query.Where("user_id = ?", pay.UserId)
 				}
 
+				if pay.AssigneeId != 0 {
+					query = query.Where("assignee_id = ?", pay.AssigneeId)
+				}
+
 				return next(query)
 			}
 		},
@@ -134,12 +141,37 @@ func (o *orderServiceImpl) OrderDraftList(
 				}
 
 				search := pay.Search
-				keyword := search.Q
+				keyword := "%" + likeEscape(search.Q) + "%"
 				switch search.SearchType {
 				case order_iface.DraftSearchType_DRAFT_SEARCH_TYPE_ORDER_REFID:
-					query = query.Where("order_ref_id LIKE ?", "%"+keyword+"%")
+					query = query.Where("order_ref_id ILIKE ?", keyword)
 				case order_iface.DraftSearchType_DRAFT_SEARCH_TYPE_RECEIPT:
-					query = query.Where("order_payload->>'receipt' LIKE ?", "%"+keyword+"%")
+					query = query.Where("order_payload->>'receipt' ILIKE ?", keyword)
+				case order_iface.DraftSearchType_DRAFT_SEARCH_TYPE_BUYER:
+					query = query.Where(
+						"order_payload->>'buyer_username' ILIKE ? OR order_payload->'address'->>'name' ILIKE ?",
+						keyword,
+						keyword,
+					)
+				case order_iface.DraftSearchType_DRAFT_SEARCH_TYPE_PHONE:
+					query = query.Where("order_payload->'address'->>'phone' ILIKE ?", keyword)
+				case order_iface.DraftSearchType_DRAFT_SEARCH_TYPE_PRODUCT:
+					// cast text supaya kena index trigram, exists memastikan cocok di nama produk
+					query = query.
+						Where("mp_products::text ILIKE ?", keyword).
+						Where("EXISTS (SELECT 1 FROM jsonb_array_elements(draft_orders.mp_products) p WHERE p->>'name' ILIKE ?)", keyword)
+				}
+
+				return next(query)
+			}
+		},
+		func(db *gorm.DB, next db_connect.NextFunc) db_connect.NextFunc { // filter ref sudah jadi order
+			return func(query *gorm.DB) (*gorm.DB, error) {
+				switch pay.OrderExist {
+				case order_iface.DraftOrderExistFilter_DRAFT_ORDER_EXIST_FILTER_EXIST:
+					query = query.Where(draft_core.OrderedCondition("draft_orders"))
+				case order_iface.DraftOrderExistFilter_DRAFT_ORDER_EXIST_FILTER_NOT_EXIST:
+					query = query.Where("NOT " + draft_core.OrderedCondition("draft_orders"))
 				}
 
 				return next(query)
@@ -163,6 +195,37 @@ func (o *orderServiceImpl) OrderDraftList(
 				)
 			}
 		},
+		func(db *gorm.DB, next db_connect.NextFunc) db_connect.NextFunc { // sorting
+			return func(query *gorm.DB) (*gorm.DB, error) {
+				desc := true
+				field := order_iface.DraftSortField_DRAFT_SORT_FIELD_CREATED
+				if pay.Sort != nil {
+					desc = pay.Sort.Desc
+					if pay.Sort.Field != order_iface.DraftSortField_DRAFT_SORT_FIELD_UNSPECIFIED {
+						field = pay.Sort.Field
+					}
+				}
+
+				var column string
+				switch field {
+				case order_iface.DraftSortField_DRAFT_SORT_FIELD_TOTAL:
+					column = "order_total"
+				case order_iface.DraftSortField_DRAFT_SORT_FIELD_SHOP:
+					column = "(SELECT m.mp_name FROM marketplaces m WHERE m.id = draft_orders.order_mp_id)"
+				default:
+					column = "created"
+				}
+
+				query = query.Order(clause.OrderBy{
+					Columns: []clause.OrderByColumn{
+						{Column: clause.Column{Name: column, Raw: true}, Desc: desc},
+						{Column: clause.Column{Name: "id"}, Desc: desc},
+					},
+				})
+
+				return next(query)
+			}
+		},
 
 		func(db *gorm.DB, next db_connect.NextFunc) db_connect.NextFunc {
 			return func(query *gorm.DB) (*gorm.DB, error) {
@@ -179,10 +242,16 @@ func (o *orderServiceImpl) OrderDraftList(
 					res.Items[i] = &order_iface.DraftItem{
 						Id:         uint64(data.ID),
 						UserId:     uint64(data.UserID),
+						AssigneeId: uint64(data.AssigneeID),
 						TeamId:     uint64(data.TeamID),
 						MpProducts: data.MpProducts,
 						Payload:    data.OrderPayload.Data(),
 						Created:    timestamppb.New(data.Created),
+						Version:    int64(data.Version),
+						UpdatedBy:  uint64(data.UpdatedBy),
+						Updated:    timestampOrNil(data.Updated),
+						IsValid:    data.IsValid,
+						Issues:     validationIssuesToProto(data.ValidationIssues),
 					}
 				}
 
@@ -193,3 +262,10 @@ func (o *orderServiceImpl) OrderDraftList(
 
 	return connect.NewResponse(&res), err
 }
+
+// likeEscaper supaya % _ dan \ di keyword dicari apa adanya, escape default ILIKE postgres adalah \
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func likeEscape(q string) string {
+	return likeEscaper.Replace(q)
+}
@@ -0,0 +1,50 @@
+package order
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/draft_core"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+)
+
+// OrderDraftTTLSet implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) OrderDraftTTLSet(
+	ctx context.Context,
+	req *connect.Request[order_iface.OrderDraftTTLSetRequest],
+) (*connect.Response[order_iface.OrderDraftTTLSetResponse], error) {
+	var err error
+
+	res := order_iface.OrderDraftTTLSetResponse{}
+	pay := req.Msg
+
+	indentity := o.
+		auth.
+		AuthIdentityFromHeader(req.Header())
+
+	err = indentity.Err()
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	err = indentity.HasPermission(authorization_iface.CheckPermissionGroup{
+		&DraftOrder{}: &authorization_iface.CheckPermission{
+			DomainID: uint(pay.TeamId),
+			Actions:  []authorization_iface.Action{authorization_iface.Update},
+		},
+	}).Err()
+
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	if pay.TtlDays <= 0 {
+		return connect.NewResponse(&res), order_errors.DraftTTLInvalid(pay.TtlDays)
+	}
+
+	db := o.db.WithContext(ctx)
+	err = draft_core.SetTTL(db, uint(pay.TeamId), int(pay.TtlDays))
+	return connect.NewResponse(&res), err
+}
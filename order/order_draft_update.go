@@ -0,0 +1,83 @@
+package order
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/draft_core"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// OrderDraftUpdate implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) OrderDraftUpdate(
+	ctx context.Context,
+	req *connect.Request[order_iface.OrderDraftUpdateRequest],
+) (*connect.Response[order_iface.OrderDraftUpdateResponse], error) {
+	var err error
+
+	res := order_iface.OrderDraftUpdateResponse{}
+	pay := req.Msg
+
+	indentity := o.
+		auth.
+		AuthIdentityFromHeader(req.Header())
+
+	agent := indentity.Identity()
+
+	err = indentity.Err()
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	err = indentity.HasPermission(authorization_iface.CheckPermissionGroup{
+		&DraftOrder{}: &authorization_iface.CheckPermission{
+			DomainID: uint(pay.TeamId),
+			Actions:  []authorization_iface.Action{authorization_iface.Update},
+		},
+	}).Err()
+
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	db := o.db.WithContext(ctx)
+
+	var ownerID uint
+	if !o.canManageDraft(agent, pay.TeamId, authorization_iface.Update) {
+		ownerID = agent.GetUserID()
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		draft, err := draft_core.Update(tx, &draft_core.UpdateDraft{
+			DraftID:    uint(pay.DraftId),
+			TeamID:     uint(pay.TeamId),
+			Version:    int(pay.Version),
+			UserID:     agent.GetUserID(),
+			OwnerID:    ownerID,
+			Payload:    pay.Payload,
+			MpProducts: pay.MpProducts,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		res.Version = int64(draft.Version)
+		res.Valid = draft.IsValid
+		res.Issues = validationIssuesToProto(draft.ValidationIssues)
+		return nil
+	})
+
+	return connect.NewResponse(&res), err
+}
+
+func timestampOrNil(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,88 @@
+package order
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/draft_core"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+)
+
+func validationIssuesToProto(issues []*draft_core.ValidationIssue) []*order_iface.DraftValidationIssue {
+	result := make([]*order_iface.DraftValidationIssue, len(issues))
+	for i, issue := range issues {
+		result[i] = &order_iface.DraftValidationIssue{
+			Field:    issue.Field,
+			Code:     issue.Code,
+			Message:  issue.Message,
+			Severity: string(issue.Severity),
+		}
+	}
+	return result
+}
+
+// OrderDraftValidate implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) OrderDraftValidate(
+	ctx context.Context,
+	req *connect.Request[order_iface.OrderDraftValidateRequest],
+) (*connect.Response[order_iface.OrderDraftValidateResponse], error) {
+	var err error
+
+	res := order_iface.OrderDraftValidateResponse{
+		Issues: []*order_iface.DraftValidationIssue{},
+	}
+	pay := req.Msg
+
+	indentity := o.
+		auth.
+		AuthIdentityFromHeader(req.Header())
+
+	err = indentity.Err()
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	err = indentity.HasPermission(authorization_iface.CheckPermissionGroup{
+		&DraftOrder{}: &authorization_iface.CheckPermission{
+			DomainID: uint(pay.TeamId),
+			Actions:  []authorization_iface.Action{authorization_iface.Read},
+		},
+	}).Err()
+
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	db := o.db.WithContext(ctx)
+
+	payload := pay.Payload
+	mpProducts := pay.MpProducts
+
+	// tanpa payload yang divalidasi draft yang tersimpan
+	if payload == nil {
+		var draft DraftOrder
+		err = db.
+			Model(&DraftOrder{}).
+			Where("id = ?", pay.DraftId).
+			Where("team_id = ?", pay.TeamId).
+			First(&draft).
+			Error
+
+		if err != nil {
+			return connect.NewResponse(&res), err
+		}
+
+		payload = draft.OrderPayload.Data()
+		mpProducts = draft.MpProducts
+	}
+
+	result, err := draft_core.NewValidator(db).Validate(uint(pay.TeamId), payload, mpProducts)
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	res.Valid = result.Valid()
+	res.Issues = validationIssuesToProto(result.Issues)
+	return connect.NewResponse(&res), nil
+}
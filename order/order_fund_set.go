@@ -4,9 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/money"
+	"github.com/pdcgo/order_service/order/order_core"
+	"github.com/pdcgo/order_service/order/tag_rule"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/order_service/order_mutation"
 	"github.com/pdcgo/schema/services/access_iface/v1"
 	"github.com/pdcgo/schema/services/order_iface/v1"
 	"github.com/pdcgo/shared/authorization"
@@ -17,27 +23,28 @@ import (
 	"gorm.io/gorm/clause"
 )
 
-// OrderFundSet implements order_ifaceconnect.OrderServiceHandler.
-func (o *orderServiceImpl) OrderFundSet(
-	ctx context.Context,
-	stream *connect.ClientStream[order_iface.OrderFundSetRequest],
-) (*connect.Response[order_iface.OrderFundSetResponse], error) {
-	var err error
-
+func (o *orderServiceImpl) orderFundActor(ctx context.Context, header http.Header) (*order_core.AdjustmentActor, error) {
 	source, err := custom_connect.GetRequestSource(ctx)
 	if err != nil {
 		return nil, err
 	}
+
+	return o.adjustmentActor(header, source, source.TeamId)
+}
+
+// adjustmentActor cek permission update order di domain team yang dituju
+func (o *orderServiceImpl) adjustmentActor(header http.Header, source *access_iface.RequestSource, teamID uint64) (*order_core.AdjustmentActor, error) {
+	var err error
 	var domainID uint
 	switch source.RequestFrom {
 	case access_iface.RequestFrom_REQUEST_FROM_ADMIN:
 		domainID = authorization.RootDomain
 	default:
-		domainID = uint(source.TeamId)
+		domainID = uint(teamID)
 	}
 
 	identity := o.auth.
-		AuthIdentityFromHeader(stream.RequestHeader())
+		AuthIdentityFromHeader(header)
 
 	agent := identity.
 		Identity()
@@ -55,150 +62,36 @@ func (o *orderServiceImpl) OrderFundSet(
 		return nil, err
 	}
 
+	actor := &order_core.AdjustmentActor{
+		UserID:      agent.IdentityID(),
+		From:        agent.GetAgentType(),
+		RequestFrom: source.RequestFrom,
+	}
+
+	return actor, nil
+}
+
+// OrderFundSet implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) OrderFundSet(
+	ctx context.Context,
+	stream *connect.ClientStream[order_iface.OrderFundSetRequest],
+) (*connect.Response[order_iface.OrderFundSetResponse], error) {
+	var err error
+
+	actor, err := o.orderFundActor(ctx, stream.RequestHeader())
+	if err != nil {
+		return nil, err
+	}
+
+	// revenue dikirim di dalam transaksi seperti MpPaymentCreate,
+	// gagal kirim membatalkan seluruh stream
 	db := o.db.WithContext(ctx)
 	err = db.Transaction(func(tx *gorm.DB) error {
 		for stream.Receive() {
-			msg := stream.Msg()
-
-			switch event := msg.Kind.(type) {
-			case *order_iface.OrderFundSetRequest_OrderFundRollback:
-				return fmt.Errorf("error orderfund stream %s", event.OrderFundRollback.Message)
-			case *order_iface.OrderFundSetRequest_OrderFundSet:
-				fundset := event.OrderFundSet
-				var ord *db_models.Order
-				switch value := fundset.OrderIdentifier.(type) {
-				case *order_iface.OrderFundSet_OrderId:
-					ord, err = o.getOrder(tx, fundset.TeamId, value.OrderId, "", false)
-				case *order_iface.OrderFundSet_OrderRefId:
-					ord, err = o.getOrder(tx, fundset.TeamId, 0, value.OrderRefId, false)
-				default:
-					return errors.New("unknown identifier orderfund")
-
-				}
-
-				if err != nil {
-					return err
-				}
-
-				// Log Adjustment
-				var ordAdjust db_models.OrderAdjustment
-				tipe := db_models.AdjOrderFund
-
-				err = tx.
-					Model(&db_models.OrderAdjustment{}).
-					Where("order_id = ?", ord.ID).
-					Where("type = ?", tipe).
-					Find(&ordAdjust).
-					Error
-
-				if err != nil {
-					return err
-				}
-
-				if ordAdjust.ID == 0 {
-					ordAdjust = db_models.OrderAdjustment{
-						OrderID: ord.ID,
-						MpID:    ord.OrderMpID,
-						At:      event.OrderFundSet.At.AsTime(),
-						Type:    tipe,
-						Amount:  event.OrderFundSet.Amount,
-						Desc:    event.OrderFundSet.Desc,
-					}
-
-					err = tx.Save(&ordAdjust).Error
-					if err != nil {
-						return err
-					}
-				} else {
-					ordAdjust.Amount = event.OrderFundSet.Amount
-					ordAdjust.At = event.OrderFundSet.At.AsTime()
-					err = tx.
-						Save(&ordAdjust).
-						Error
-					if err != nil {
-						return err
-					}
-				}
-
-				// log.Println("send to revenue")
-			case *order_iface.OrderFundSetRequest_OrderCompletedSet:
-				completedSet := event.OrderCompletedSet
-
-				var ord *db_models.Order
-				switch value := completedSet.OrderIdentifier.(type) {
-				case *order_iface.OrderCompletedSet_OrderId:
-					ord, err = o.getOrder(tx, completedSet.TeamId, value.OrderId, "", true)
-				case *order_iface.OrderCompletedSet_OrderRefId:
-					ord, err = o.getOrder(tx, completedSet.TeamId, 0, value.OrderRefId, true)
-				default:
-					return errors.New("unknown identifier orderfund")
-
-				}
-
-				if err != nil {
-					return err
-				}
-
-				err = tx.
-					Model(&db_models.Order{}).
-					Where("id = ?", ord.ID).
-					Updates(map[string]interface{}{
-						"wd_total":   completedSet.Amount,
-						"wd_fund":    true,
-						"wd_fund_at": completedSet.WdAt.AsTime(),
-						"status":     db_models.OrdCompleted,
-					}).
-					Error
-
-				if err != nil {
-					return err
-				}
-
-				// log adjustment change
-				err = tx.
-					Model(&db_models.OrderAdjustment{}).
-					Where("order_id = ?", ord.ID).
-					Where("type = ?", db_models.AdjOrderFund).
-					Updates(map[string]interface{}{
-						"fund_at": completedSet.WdAt.AsTime(),
-					}).
-					Error
-
-				if err != nil {
-					return err
-				}
-
-				// log completed
-				ts := db_models.OrderTimestamp{
-					OrderID:     ord.ID,
-					UserID:      agent.IdentityID(),
-					OrderStatus: db_models.OrdCompleted,
-					Timestamp:   time.Now(),
-					From:        agent.GetAgentType(),
-				}
-				err = tx.Save(&ts).Error
-
-				if err != nil {
-					return err
-				}
-
-				// removing tag related
-				err = tx.
-					Model(&db_models.OrderTagRelation{}).
-					Where("relation_from = ?", db_models.RelationFromTracking).
-					Where("order_id = ?", ord.ID).
-					Delete(&db_models.OrderTagRelation{}).
-					Error
-
-				if err != nil {
-					return err
-				}
-
-			default:
-				return errors.New("unknown event orderfund")
-
+			_, err = o.applyOrderFundEvent(ctx, tx, actor, stream.Msg())
+			if err != nil {
+				return err
 			}
-
 		}
 
 		return stream.Err()
@@ -207,6 +100,184 @@ func (o *orderServiceImpl) OrderFundSet(
 	return &connect.Response[order_iface.OrderFundSetResponse]{}, err
 }
 
+func (o *orderServiceImpl) applyOrderFundEvent(
+	ctx context.Context,
+	tx *gorm.DB,
+	actor *order_core.AdjustmentActor,
+	msg *order_iface.OrderFundSetRequest,
+) (*db_models.Order, error) {
+	switch event := msg.Kind.(type) {
+	case *order_iface.OrderFundSetRequest_OrderFundRollback:
+		return nil, fmt.Errorf("error orderfund stream %s", event.OrderFundRollback.Message)
+	case *order_iface.OrderFundSetRequest_OrderFundSet:
+		return o.applyOrderFund(ctx, tx, actor, event.OrderFundSet)
+	case *order_iface.OrderFundSetRequest_OrderCompletedSet:
+		return o.applyOrderCompleted(tx, actor, event.OrderCompletedSet)
+	default:
+		return nil, errors.New("unknown event orderfund")
+	}
+}
+
+func (o *orderServiceImpl) applyOrderFund(
+	ctx context.Context,
+	tx *gorm.DB,
+	actor *order_core.AdjustmentActor,
+	fundset *order_iface.OrderFundSet,
+) (*db_models.Order, error) {
+	var err error
+	var ord *db_models.Order
+	switch value := fundset.OrderIdentifier.(type) {
+	case *order_iface.OrderFundSet_OrderId:
+		ord, err = o.getOrder(tx, fundset.TeamId, value.OrderId, "", false)
+	case *order_iface.OrderFundSet_OrderRefId:
+		ord, err = o.getOrder(tx, fundset.TeamId, 0, value.OrderRefId, false)
+	default:
+		return nil, order_errors.MissingIdentifier()
+
+	}
+
+	if err != nil {
+		return ord, err
+	}
+
+	// order fund selalu mengikuti currency order
+	pay := &order_iface.MpPaymentCreateRequest{
+		TeamId:  fundset.TeamId,
+		OrderId: uint64(ord.ID),
+		ShopId:  uint64(ord.OrderMpID),
+		Type:    string(db_models.AdjOrderFund),
+		Amount:  fundset.Amount,
+		At:      fundset.At,
+		Desc:    fundset.Desc,
+	}
+
+	err = o.setOrderFundPayment(ctx, tx, actor, pay)
+	return ord, err
+}
+
+// setOrderFundPayment order fund lewat OrderPaymentManage yang sama dengan MpPaymentCreate
+func (o *orderServiceImpl) setOrderFundPayment(
+	ctx context.Context,
+	tx *gorm.DB,
+	actor *order_core.AdjustmentActor,
+	pay *order_iface.MpPaymentCreateRequest,
+) error {
+	ordPayment := order_core.
+		NewOrderPaymentManage(tx, uint(pay.OrderId), actor.UserID, pay).
+		SetAgent(actor.From, actor.RequestFrom).
+		MatchByType()
+
+	err := ordPayment.Create()
+	if err != nil {
+		return err
+	}
+
+	return o.sendPaymentRevenue(ctx, ordPayment, pay)
+}
+
+func (o *orderServiceImpl) applyOrderCompleted(
+	tx *gorm.DB,
+	actor *order_core.AdjustmentActor,
+	completedSet *order_iface.OrderCompletedSet,
+) (*db_models.Order, error) {
+	var err error
+	var ord *db_models.Order
+	switch value := completedSet.OrderIdentifier.(type) {
+	case *order_iface.OrderCompletedSet_OrderId:
+		ord, err = o.getOrder(tx, completedSet.TeamId, value.OrderId, "", true)
+	case *order_iface.OrderCompletedSet_OrderRefId:
+		ord, err = o.getOrder(tx, completedSet.TeamId, 0, value.OrderRefId, true)
+	default:
+		return nil, order_errors.MissingIdentifier()
+
+	}
+
+	if err != nil {
+		return ord, err
+	}
+
+	currency, err := order_core.GetOrderCurrency(tx, ord.ID)
+	if err != nil {
+		return ord, err
+	}
+
+	err = tx.
+		Model(&db_models.Order{}).
+		Where("id = ?", ord.ID).
+		Updates(map[string]interface{}{
+			"wd_total":   money.FromFloat(currency, completedSet.Amount).Float(),
+			"wd_fund":    true,
+			"wd_fund_at": completedSet.WdAt.AsTime(),
+			"status":     db_models.OrdCompleted,
+		}).
+		Error
+
+	if err != nil {
+		return ord, err
+	}
+
+	// completed hanya mengisi fund_at adjustment order fund yang sudah ada,
+	// nominal tetap dari order fund set
+	adjs := []*db_models.OrderAdjustment{}
+	err = tx.
+		Model(&db_models.OrderAdjustment{}).
+		Where("order_id = ?", ord.ID).
+		Where("type = ?", db_models.AdjOrderFund).
+		Where("deleted = ?", false).
+		Find(&adjs).
+		Error
+
+	if err != nil {
+		return ord, err
+	}
+
+	fundAt := completedSet.WdAt.AsTime()
+	for _, adj := range adjs {
+		adj.FundAt = fundAt
+		err = tx.
+			Model(&db_models.OrderAdjustment{}).
+			Where("id = ?", adj.ID).
+			Update("fund_at", fundAt).
+			Error
+
+		if err != nil {
+			return ord, err
+		}
+
+		err = order_core.RecordAdjustmentLog(tx, actor, db_models.AdjLogUpdated, adj, currency)
+		if err != nil {
+			return ord, err
+		}
+	}
+
+	// log completed
+	ts := db_models.OrderTimestamp{
+		OrderID:     ord.ID,
+		UserID:      actor.UserID,
+		OrderStatus: db_models.OrdCompleted,
+		Timestamp:   time.Now(),
+		From:        actor.From,
+	}
+	err = tx.Save(&ts).Error
+
+	if err != nil {
+		return ord, err
+	}
+
+	// removing tag related
+	err = order_mutation.
+		NewTagMutation(tx, order_mutation.WithActor(actor.UserID, actor.From)).
+		RemoveAllFrom(db_models.RelationFromTracking, []uint{ord.ID})
+
+	if err != nil {
+		return ord, err
+	}
+
+	// tag rule dievaluasi ulang setelah status berubah
+	_, err = tag_rule.NewEngine(tx, ts.Timestamp).Evaluate([]uint{ord.ID})
+	return ord, err
+}
+
 func (o *orderServiceImpl) getOrder(
 	tx *gorm.DB,
 	teamID uint64,
@@ -215,36 +286,49 @@ func (o *orderServiceImpl) getOrder(
 	lock bool,
 ) (*db_models.Order, error) {
 	if orderID == 0 && ordRefID == "" {
-		return nil, errors.New("empty orderid or ref id")
+		return nil, order_errors.MissingIdentifier()
 	}
 
+	ord := db_models.Order{}
+	query := func(tx *gorm.DB) error {
+		return tx.
+			Model(&db_models.Order{}).
+			Where("team_id = ?", teamID).
+			Where("(id = ?) or (order_ref_id = ?)", orderID, ordRefID).
+			Where("status NOT IN ?", []db_models.OrdStatus{
+				db_models.OrdCancel,
+			}).
+			Find(&ord).
+			Error
+	}
+
+	var err error
 	if lock {
-		tx = tx.Clauses(clause.Locking{
-			Strength: "UPDATE",
-			Options:  "NOWAIT",
+		err = order_core.DefaultLockRetry.Do(tx.Statement.Context, tx, "order_lock", func(tx *gorm.DB) error {
+			return query(tx.Clauses(clause.Locking{
+				Strength: "UPDATE",
+				Options:  "NOWAIT",
+			}))
 		})
-	}
 
-	ord := db_models.Order{}
-	err := tx.
-		Model(&db_models.Order{}).
-		Where("team_id = ?", teamID).
-		Where("(id = ?) or (order_ref_id = ?)", orderID, ordRefID).
-		Where("status NOT IN ?", []db_models.OrdStatus{
-			db_models.OrdCancel,
-		}).
-		Find(&ord).
-		Error
+		if order_core.IsLockNotAvailable(err) {
+			// order tanpa lock hanya untuk info id yang bentrok
+			_ = query(tx)
+			return nil, order_errors.LockConflict(uint64(ord.ID), ordRefID, err)
+		}
+	} else {
+		err = query(tx)
+	}
 
 	if err != nil {
 		return nil, err
 	}
 	if ord.ID == 0 {
-		return nil, fmt.Errorf("order dengan id %s tidak ditemukan", ordRefID)
+		return nil, order_errors.OrderNotFound(orderID, ordRefID)
 	}
 
 	if ord.OrderMpID == 0 {
-		return &ord, fmt.Errorf("refmode order with id %s marketplace not set %d with receipt %s with ref %s id %d", ord.OrderRefID, ord.ID, ord.Receipt, ordRefID, orderID)
+		return &ord, order_errors.MarketplaceNotSet(uint64(ord.ID), ord.OrderRefID)
 	}
 
 	return &ord, err
@@ -0,0 +1,109 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/order_core"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"gorm.io/gorm"
+)
+
+// OrderFundSetStream implements order_ifaceconnect.OrderServiceHandler.
+// tiap pesan di-ack dengan hasilnya, baris yang gagal hanya membatalkan
+// savepoint baris itu saja. baris yang berhasil di-commit di akhir stream.
+func (o *orderServiceImpl) OrderFundSetStream(
+	ctx context.Context,
+	stream *connect.BidiStream[order_iface.OrderFundSetRequest, order_iface.OrderFundSetStreamResponse],
+) error {
+	var err error
+
+	actor, err := o.orderFundActor(ctx, stream.RequestHeader())
+	if err != nil {
+		return err
+	}
+
+	summary := order_iface.OrderFundSetSummary{}
+	lang := order_errors.Language(stream.RequestHeader().Get("Accept-Language"))
+
+	db := o.db.WithContext(ctx)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var seq uint64
+		for {
+			msg, err := stream.Receive()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			seq++
+			summary.Total++
+
+			// rollback dari client membatalkan semua baris
+			if event, ok := msg.Kind.(*order_iface.OrderFundSetRequest_OrderFundRollback); ok {
+				return connect.NewError(
+					connect.CodeAborted,
+					fmt.Errorf("error orderfund stream %s", event.OrderFundRollback.Message),
+				)
+			}
+
+			ack := order_iface.OrderFundSetAck{
+				Seq:    seq,
+				Status: order_iface.OrderFundAckStatus_ORDER_FUND_ACK_STATUS_APPLIED,
+			}
+
+			// revenue dikirim di dalam savepoint, gagal kirim hanya menggagalkan baris itu
+			rowErr, err := order_core.WithSavePoint(tx, "order_fund_row", func(tx *gorm.DB) error {
+				ord, err := o.applyOrderFundEvent(ctx, tx, actor, msg)
+				if ord != nil {
+					ack.OrderId = uint64(ord.ID)
+				}
+				return err
+			})
+
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case rowErr == nil:
+				summary.Applied++
+			case errors.Is(rowErr, order_errors.ErrOrderNotFound):
+				summary.Skipped++
+				ack.Status = order_iface.OrderFundAckStatus_ORDER_FUND_ACK_STATUS_SKIPPED
+				ack.Message = order_errors.Message(rowErr, lang)
+			default:
+				summary.Failed++
+				ack.Status = order_iface.OrderFundAckStatus_ORDER_FUND_ACK_STATUS_FAILED
+				ack.Message = order_errors.Message(rowErr, lang)
+			}
+
+			err = stream.Send(&order_iface.OrderFundSetStreamResponse{
+				Kind: &order_iface.OrderFundSetStreamResponse_Ack{
+					Ack: &ack,
+				},
+			})
+
+			if err != nil {
+				return err
+			}
+		}
+	})
+
+	if err != nil {
+		return err
+	}
+
+	// summary dikirim setelah commit supaya applied benar benar tersimpan
+	return stream.Send(&order_iface.OrderFundSetStreamResponse{
+		Kind: &order_iface.OrderFundSetStreamResponse_Summary{
+			Summary: &summary,
+		},
+	})
+}
@@ -0,0 +1,188 @@
+package order
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/money"
+	"github.com/pdcgo/order_service/order/order_core"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/schema/services/access_iface/v1"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/authorization"
+	"github.com/pdcgo/shared/custom_connect"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+)
+
+// OrderProfitLoss implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) OrderProfitLoss(
+	ctx context.Context,
+	req *connect.Request[order_iface.OrderProfitLossRequest],
+) (*connect.Response[order_iface.OrderProfitLossResponse], error) {
+	var err error
+
+	source, err := custom_connect.GetRequestSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pay := req.Msg
+
+	var domainID uint
+	switch source.RequestFrom {
+	case access_iface.RequestFrom_REQUEST_FROM_ADMIN:
+		domainID = authorization.RootDomain
+	default:
+		domainID = uint(pay.TeamId)
+	}
+
+	err = o.auth.
+		AuthIdentityFromHeader(req.Header()).
+		HasPermission(authorization_iface.CheckPermissionGroup{
+			&db_models.Order{}: &authorization_iface.CheckPermission{
+				DomainID: domainID,
+				Actions:  []authorization_iface.Action{authorization_iface.Read},
+			},
+		}).
+		Err()
+
+	if err != nil {
+		return nil, err
+	}
+
+	db := o.db.WithContext(ctx)
+
+	var ord struct {
+		ID           uint
+		TeamID       uint
+		OrderMpTotal float64
+		WdTotal      float64
+		Currency     money.Currency
+	}
+	err = db.
+		Model(&order_core.OrderCurrency{}).
+		Select("id", "team_id", "order_mp_total", "wd_total", "currency").
+		Where("id = ?", pay.OrderId).
+		Find(&ord).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	if ord.ID == 0 {
+		return nil, order_errors.OrderNotFound(pay.OrderId, "")
+	}
+
+	if domainID != authorization.RootDomain && uint64(ord.TeamID) != pay.TeamId {
+		return nil, order_errors.WrongTeam(pay.OrderId, pay.TeamId)
+	}
+
+	adjs := OrderAdjustmentList{}
+	err = db.
+		Model(&db_models.OrderAdjustment{}).
+		Where("order_id = ?", ord.ID).
+		Where("deleted = ?", false).
+		Order("at asc").
+		Find(&adjs).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	currencies, err := order_core.GetAdjustmentCurrencies(db, adjs.IDs())
+	if err != nil {
+		return nil, err
+	}
+
+	currency := ord.Currency.OrDefault()
+	mpTotal := money.FromFloat(currency, ord.OrderMpTotal)
+	wdTotal := money.FromFloat(currency, ord.WdTotal)
+
+	// dijumlah per type adjustment dan revenue type, revenue type mengikuti yang dikirim
+	// ke revenue service dan bisa beda per tanda amount (other cost / other revenue)
+	type itemKey struct {
+		adjType db_models.AdjustmentType
+		revType string
+	}
+
+	keys := []itemKey{}
+	sums := map[itemKey]money.Amount{}
+	items := map[itemKey]*order_iface.ProfitLossItem{}
+	for _, adj := range adjs {
+		adjCurrency := currencies[adj.ID].OrDefault()
+		if adjCurrency != currency {
+			return nil, order_errors.CurrencyMismatch(uint64(ord.ID), "", string(currency), string(adjCurrency))
+		}
+
+		// adjustment yang tidak termapping tidak pernah dikirim ke revenue service
+		key := itemKey{adjType: adj.Type}
+		rtype, err := o.getType(adj)
+		if err == nil {
+			key.revType = rtype.String()
+		}
+
+		item := items[key]
+		if item == nil {
+			item = &order_iface.ProfitLossItem{
+				Type:        string(adj.Type),
+				RevenueType: key.revType,
+			}
+			items[key] = item
+			sums[key] = money.New(currency, 0)
+			keys = append(keys, key)
+		}
+
+		sum, err := sums[key].Add(money.FromFloat(currency, adj.Amount))
+		if err != nil {
+			return nil, err
+		}
+
+		sums[key] = sum
+		item.Count++
+	}
+
+	diff, err := wdTotal.Sub(mpTotal)
+	if err != nil {
+		return nil, err
+	}
+
+	result := order_iface.OrderProfitLossResponse{
+		OrderId:  uint64(ord.ID),
+		Currency: string(currency),
+		MpTotal:  mpTotal.Float(),
+		WdTotal:  wdTotal.Float(),
+		EstDiff:  diff.Float(),
+		Items:    []*order_iface.ProfitLossItem{},
+	}
+
+	// ringkasan per type adjustment, gabungan semua revenue type
+	totals := map[db_models.AdjustmentType]money.Amount{}
+	for _, key := range keys {
+		item := items[key]
+		item.Amount = sums[key].Float()
+		result.Items = append(result.Items, item)
+
+		total, ok := totals[key.adjType]
+		if !ok {
+			total = money.New(currency, 0)
+		}
+
+		total, err = total.Add(sums[key])
+		if err != nil {
+			return nil, err
+		}
+		totals[key.adjType] = total
+	}
+
+	result.Commission = totals[db_models.AdjCommision].Float()
+	result.Premi = totals[db_models.AdjPremi].Float()
+	result.Shipping = totals[db_models.AdjShipping].Float()
+	result.Packaging = totals[db_models.AdjPackaging].Float()
+	result.ReturnCost = totals[db_models.AdjReturn].Float()
+	result.LostCompensation = totals[db_models.AdjLostCompensation].Float()
+
+	return connect.NewResponse(&result), nil
+}
@@ -2,9 +2,11 @@ package order
 
 import (
 	"context"
-	"errors"
+	"time"
 
 	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/tag_rule"
+	"github.com/pdcgo/order_service/order_errors"
 	"github.com/pdcgo/schema/services/order_iface/v1"
 	"github.com/pdcgo/schema/services/revenue_iface/v1"
 	"github.com/pdcgo/shared/db_models"
@@ -56,7 +58,7 @@ func (o *orderServiceImpl) OrderReturnArrived(
 		}
 
 		if ord.ID == 0 {
-			return errors.New("order is not return")
+			return order_errors.ReturnNotFound(pay.TxId)
 		}
 
 		// getting and lock payment
@@ -106,7 +108,9 @@ func (o *orderServiceImpl) OrderReturnArrived(
 			return err
 		}
 
-		return nil
+		// tag rule dievaluasi ulang setelah status berubah
+		_, err = tag_rule.NewEngine(tx, time.Now()).Evaluate([]uint{ord.ID})
+		return err
 	})
 
 	if err != nil {
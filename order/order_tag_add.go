@@ -2,64 +2,159 @@ package order
 
 import (
 	"context"
+	"slices"
 
 	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/order_service/order_mutation"
+	"github.com/pdcgo/schema/services/access_iface/v1"
 	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/authorization"
+	"github.com/pdcgo/shared/custom_connect"
 	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+	"gorm.io/gorm"
 )
 
+func tagRelationFrom(tagType order_iface.TagType) db_models.RelationFrom {
+	switch tagType {
+	case order_iface.TagType_TAG_TYPE_TRACKING:
+		return db_models.RelationFromTracking
+	case order_iface.TagType_TAG_TYPE_WAREHOUSE:
+		return db_models.RelationFromWarehouse
+	}
+	return db_models.RelationFromUser
+}
+
+// tagTypeAllowed tag sistem dibuat di team sistem, user biasa hanya boleh tag user
+func tagTypeAllowed(tagType order_iface.TagType, requestFrom access_iface.RequestFrom) bool {
+	switch requestFrom {
+	case access_iface.RequestFrom_REQUEST_FROM_ADMIN,
+		access_iface.RequestFrom_REQUEST_FROM_SYSTEM:
+		return true
+	}
+
+	switch tagType {
+	case order_iface.TagType_TAG_TYPE_TRACKING:
+		return false
+	case order_iface.TagType_TAG_TYPE_WAREHOUSE:
+		return requestFrom == access_iface.RequestFrom_REQUEST_FROM_WAREHOUSE
+	}
+	return true
+}
+
 // OrderTagAdd implements order_ifaceconnect.OrderServiceHandler.
 func (o *orderServiceImpl) OrderTagAdd(
 	ctx context.Context,
 	req *connect.Request[order_iface.OrderTagAddRequest],
 ) (*connect.Response[order_iface.OrderTagAddResponse], error) {
 	var err error
+
+	source, err := custom_connect.GetRequestSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	pay := req.Msg
 
-	err = o.
-		auth.
-		AuthIdentityFromHeader(req.Header()).
+	var domainID uint
+	switch source.RequestFrom {
+	case access_iface.RequestFrom_REQUEST_FROM_ADMIN:
+		domainID = authorization.RootDomain
+	default:
+		domainID = uint(source.TeamId)
+		if pay.TeamId != source.TeamId {
+			return nil, order_errors.WrongTeam(pay.OrderId, pay.TeamId)
+		}
+	}
+
+	identity := o.auth.AuthIdentityFromHeader(req.Header())
+	agent := identity.Identity()
+	err = identity.
+		HasPermission(authorization_iface.CheckPermissionGroup{
+			&db_models.Order{}: &authorization_iface.CheckPermission{
+				DomainID: domainID,
+				Actions:  []authorization_iface.Action{authorization_iface.Update},
+			},
+		}).
 		Err()
 
 	if err != nil {
 		return nil, err
 	}
 
-	db := o.db.WithContext(ctx)
+	orderIDs := []uint{}
+	if pay.OrderId != 0 {
+		orderIDs = append(orderIDs, uint(pay.OrderId))
+	}
+	for _, orderID := range pay.OrderIds {
+		if !slices.Contains(orderIDs, uint(orderID)) {
+			orderIDs = append(orderIDs, uint(orderID))
+		}
+	}
+
+	if len(orderIDs) == 0 || len(pay.Tags) == 0 {
+		return connect.NewResponse(&order_iface.OrderTagAddResponse{}), nil
+	}
 
+	// tag dikelompokkan per relation_from supaya satu kali insert per sumber
+	froms := []db_models.RelationFrom{}
+	tags := map[db_models.RelationFrom][]string{}
 	for _, tagp := range pay.Tags {
-		tag := db_models.OrderTag{
-			Name: tagp.Value,
+		if !tagTypeAllowed(tagp.Type, source.RequestFrom) {
+			return nil, order_errors.TagTypeDenied(tagp.Type.String(), tagp.Value)
 		}
 
-		err = db.
-			Model(&db_models.OrderTag{}).
-			Where("name = ?", tag.Name).
-			Find(&tag).
+		from := tagRelationFrom(tagp.Type)
+		if _, ok := tags[from]; !ok {
+			froms = append(froms, from)
+		}
+		tags[from] = append(tags[from], tagp.Value)
+	}
+
+	err = o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		orders := []*db_models.Order{}
+		err := tx.
+			Model(&db_models.Order{}).
+			Select("id", "team_id").
+			Where("id IN ?", orderIDs).
+			Find(&orders).
 			Error
 
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		if tag.ID == 0 {
-			err = db.Save(&tag).Error
-			if err != nil {
-				return nil, err
-			}
+		found := map[uint]*db_models.Order{}
+		for _, ord := range orders {
+			found[ord.ID] = ord
 		}
 
-		rel := &db_models.OrderTagRelation{
-			OrderID:      uint(pay.OrderId),
-			OrderTagID:   tag.ID,
-			RelationFrom: order_iface.TagType_name[int32(tagp.Type)],
+		for _, orderID := range orderIDs {
+			ord := found[orderID]
+			if ord == nil {
+				return order_errors.OrderNotFound(uint64(orderID), "")
+			}
+
+			if domainID != authorization.RootDomain && uint64(ord.TeamID) != pay.TeamId {
+				return order_errors.WrongTeam(uint64(orderID), pay.TeamId)
+			}
 		}
-		err = db.Save(rel).Error
-		if err != nil {
-			return nil, err
+
+		tagmut := order_mutation.NewTagMutation(tx, order_mutation.WithActor(agent.IdentityID(), agent.GetAgentType()))
+		for _, from := range froms {
+			err = tagmut.Add(from, orderIDs, tags[from])
+			if err != nil {
+				return err
+			}
 		}
-	}
 
-	return &connect.Response[order_iface.OrderTagAddResponse]{}, nil
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
 
+	return connect.NewResponse(&order_iface.OrderTagAddResponse{}), nil
 }
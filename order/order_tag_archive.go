@@ -0,0 +1,38 @@
+package order
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/tag_core"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+)
+
+// OrderTagArchive implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) OrderTagArchive(
+	ctx context.Context,
+	req *connect.Request[order_iface.OrderTagArchiveRequest],
+) (*connect.Response[order_iface.OrderTagArchiveResponse], error) {
+	var err error
+
+	res := order_iface.OrderTagArchiveResponse{}
+	pay := req.Msg
+
+	indentity := o.
+		auth.
+		AuthIdentityFromHeader(req.Header())
+
+	err = indentity.Err()
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	err = o.checkTagCatalogue(indentity, pay.TeamId, authorization_iface.Update)
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	err = tag_core.Archive(o.db.WithContext(ctx), uint(pay.TeamId), uint(pay.TagId), pay.Archived)
+	return connect.NewResponse(&res), err
+}
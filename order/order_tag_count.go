@@ -0,0 +1,85 @@
+package order
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/order_view"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+)
+
+// OrderTagCount implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) OrderTagCount(
+	ctx context.Context,
+	req *connect.Request[order_iface.OrderTagCountRequest],
+) (*connect.Response[order_iface.OrderTagCountResponse], error) {
+	var err error
+
+	res := order_iface.OrderTagCountResponse{
+		Counts: []*order_iface.OrderTagCountItem{},
+	}
+	pay := req.Msg
+
+	indentity := o.
+		auth.
+		AuthIdentityFromHeader(req.Header())
+
+	agent := indentity.Identity()
+	err = indentity.HasPermission(authorization_iface.CheckPermissionGroup{
+		&db_models.Order{}: &authorization_iface.CheckPermission{
+			DomainID: uint(pay.TeamId),
+			Actions:  []authorization_iface.Action{authorization_iface.Read},
+		},
+	}).Err()
+
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	db := o.db.WithContext(ctx)
+
+	filter := order_view.Filter{}
+	if pay.ViewId != 0 {
+		view, err := order_view.Get(db, uint(pay.TeamId), agent.IdentityID(), uint(pay.ViewId))
+		if err != nil {
+			return connect.NewResponse(&res), err
+		}
+		filter = view.Filter.Data()
+	}
+
+	if len(pay.Statuses) != 0 {
+		filter.Statuses = []db_models.OrdStatus{}
+		for _, status := range pay.Statuses {
+			filter.Statuses = append(filter.Statuses, db_models.OrdStatus(status))
+		}
+	}
+
+	if pay.From != nil {
+		from := pay.From.AsTime()
+		filter.From = &from
+	}
+
+	if pay.To != nil {
+		to := pay.To.AsTime()
+		filter.To = &to
+	}
+
+	counts, err := order_view.TagCounts(db, uint(pay.TeamId), &filter)
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	for _, count := range counts {
+		res.Counts = append(res.Counts, &order_iface.OrderTagCountItem{
+			TagId:  uint64(count.TagID),
+			Name:   count.Name,
+			Color:  count.Color,
+			System: count.TeamID == 0,
+			Count:  count.Count,
+		})
+	}
+
+	return connect.NewResponse(&res), nil
+}
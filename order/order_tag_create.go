@@ -0,0 +1,48 @@
+package order
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/tag_core"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+)
+
+// OrderTagCreate implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) OrderTagCreate(
+	ctx context.Context,
+	req *connect.Request[order_iface.OrderTagCreateRequest],
+) (*connect.Response[order_iface.OrderTagCreateResponse], error) {
+	var err error
+
+	res := order_iface.OrderTagCreateResponse{}
+	pay := req.Msg
+
+	indentity := o.
+		auth.
+		AuthIdentityFromHeader(req.Header())
+
+	err = indentity.Err()
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	err = o.checkTagCatalogue(indentity, pay.TeamId, authorization_iface.Create)
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	tag, err := tag_core.Create(o.db.WithContext(ctx), uint(pay.TeamId), &tag_core.TagData{
+		Name:        pay.Name,
+		Color:       pay.Color,
+		Description: pay.Description,
+	})
+
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	res.Tag = tagCatalogueToProto(tag)
+	return connect.NewResponse(&res), nil
+}
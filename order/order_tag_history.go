@@ -0,0 +1,98 @@
+package order
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/tag_core"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/schema/services/access_iface/v1"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/authorization"
+	"github.com/pdcgo/shared/custom_connect"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// OrderTagHistoryList implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) OrderTagHistoryList(
+	ctx context.Context,
+	req *connect.Request[order_iface.OrderTagHistoryListRequest],
+) (*connect.Response[order_iface.OrderTagHistoryListResponse], error) {
+	var err error
+
+	source, err := custom_connect.GetRequestSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pay := req.Msg
+
+	var domainID uint
+	switch source.RequestFrom {
+	case access_iface.RequestFrom_REQUEST_FROM_ADMIN:
+		domainID = authorization.RootDomain
+	default:
+		domainID = uint(pay.TeamId)
+	}
+
+	err = o.auth.
+		AuthIdentityFromHeader(req.Header()).
+		HasPermission(authorization_iface.CheckPermissionGroup{
+			&db_models.Order{}: &authorization_iface.CheckPermission{
+				DomainID: domainID,
+				Actions:  []authorization_iface.Action{authorization_iface.Read},
+			},
+		}).
+		Err()
+
+	if err != nil {
+		return nil, err
+	}
+
+	db := o.db.WithContext(ctx)
+	result := order_iface.OrderTagHistoryListResponse{
+		Items: []*order_iface.OrderTagHistoryItem{},
+	}
+
+	var ord db_models.Order
+	err = db.
+		Model(&db_models.Order{}).
+		Select("id", "team_id").
+		Where("id = ?", pay.OrderId).
+		Find(&ord).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	if ord.ID == 0 {
+		return nil, order_errors.OrderNotFound(pay.OrderId, "")
+	}
+
+	if domainID != authorization.RootDomain && uint64(ord.TeamID) != pay.TeamId {
+		return nil, order_errors.WrongTeam(pay.OrderId, pay.TeamId)
+	}
+
+	histories, err := tag_core.History(db, ord.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hist := range histories {
+		result.Items = append(result.Items, &order_iface.OrderTagHistoryItem{
+			Id:           uint64(hist.ID),
+			TagId:        uint64(hist.OrderTagID),
+			TagName:      hist.TagName,
+			Action:       string(hist.Action),
+			RelationFrom: hist.RelationFrom,
+			UserId:       uint64(hist.UserID),
+			From:         string(hist.From),
+			Timestamp:    timestamppb.New(hist.Timestamp),
+		})
+	}
+
+	return connect.NewResponse(&result), nil
+}
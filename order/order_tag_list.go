@@ -0,0 +1,81 @@
+package order
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/tag_core"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/authorization"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+)
+
+func tagCatalogueToProto(tag *tag_core.TagCatalogue) *order_iface.OrderTagCatalogueItem {
+	return &order_iface.OrderTagCatalogueItem{
+		Id:          uint64(tag.ID),
+		TeamId:      uint64(tag.TeamID),
+		Name:        tag.Name,
+		Slug:        tag.Slug,
+		Color:       tag.Color,
+		Description: tag.Description,
+		Archived:    tag.Archived,
+	}
+}
+
+// checkTagCatalogue tag team dicek di domain team, tag sistem (team 0) hanya dari root domain
+func (o *orderServiceImpl) checkTagCatalogue(identity authorization_iface.AuthIdentity, teamID uint64, action authorization_iface.Action) error {
+	domainID := uint(teamID)
+	if domainID == tag_core.SystemTeamID {
+		domainID = authorization.RootDomain
+	}
+
+	return identity.HasPermission(authorization_iface.CheckPermissionGroup{
+		&tag_core.TagCatalogue{}: &authorization_iface.CheckPermission{
+			DomainID: domainID,
+			Actions:  []authorization_iface.Action{action},
+		},
+	}).Err()
+}
+
+// OrderTagList implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) OrderTagList(
+	ctx context.Context,
+	req *connect.Request[order_iface.OrderTagListRequest],
+) (*connect.Response[order_iface.OrderTagListResponse], error) {
+	var err error
+
+	res := order_iface.OrderTagListResponse{
+		Tags: []*order_iface.OrderTagCatalogueItem{},
+	}
+	pay := req.Msg
+
+	indentity := o.
+		auth.
+		AuthIdentityFromHeader(req.Header())
+
+	err = indentity.Err()
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	err = o.checkTagCatalogue(indentity, pay.TeamId, authorization_iface.Read)
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	tags, err := tag_core.List(o.db.WithContext(ctx), &tag_core.ListFilter{
+		TeamID:          uint(pay.TeamId),
+		Q:               pay.Q,
+		IncludeArchived: pay.IncludeArchived,
+	})
+
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	for _, tag := range tags {
+		res.Tags = append(res.Tags, tagCatalogueToProto(tag))
+	}
+
+	return connect.NewResponse(&res), nil
+}
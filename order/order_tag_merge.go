@@ -0,0 +1,59 @@
+package order
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/tag_core"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+	"gorm.io/gorm"
+)
+
+// OrderTagMerge implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) OrderTagMerge(
+	ctx context.Context,
+	req *connect.Request[order_iface.OrderTagMergeRequest],
+) (*connect.Response[order_iface.OrderTagMergeResponse], error) {
+	var err error
+
+	res := order_iface.OrderTagMergeResponse{}
+	pay := req.Msg
+
+	indentity := o.
+		auth.
+		AuthIdentityFromHeader(req.Header())
+
+	err = indentity.Err()
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	err = o.checkTagCatalogue(indentity, pay.TeamId, authorization_iface.Update)
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	sourceIDs := make([]uint, len(pay.SourceTagIds))
+	for i, id := range pay.SourceTagIds {
+		sourceIDs[i] = uint(id)
+	}
+
+	err = o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		agent := indentity.Identity()
+		actor := &tag_core.TagActor{
+			UserID: agent.IdentityID(),
+			From:   agent.GetAgentType(),
+		}
+
+		moved, err := tag_core.Merge(tx, actor, uint(pay.TeamId), uint(pay.TargetTagId), sourceIDs)
+		res.Moved = moved
+		return err
+	})
+
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	return connect.NewResponse(&res), nil
+}
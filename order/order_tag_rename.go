@@ -0,0 +1,48 @@
+package order
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/tag_core"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+)
+
+// OrderTagRename implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) OrderTagRename(
+	ctx context.Context,
+	req *connect.Request[order_iface.OrderTagRenameRequest],
+) (*connect.Response[order_iface.OrderTagRenameResponse], error) {
+	var err error
+
+	res := order_iface.OrderTagRenameResponse{}
+	pay := req.Msg
+
+	indentity := o.
+		auth.
+		AuthIdentityFromHeader(req.Header())
+
+	err = indentity.Err()
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	err = o.checkTagCatalogue(indentity, pay.TeamId, authorization_iface.Update)
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	tag, err := tag_core.Rename(o.db.WithContext(ctx), uint(pay.TeamId), uint(pay.TagId), &tag_core.TagData{
+		Name:        pay.Name,
+		Color:       pay.Color,
+		Description: pay.Description,
+	})
+
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	res.Tag = tagCatalogueToProto(tag)
+	return connect.NewResponse(&res), nil
+}
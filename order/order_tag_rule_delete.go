@@ -0,0 +1,42 @@
+package order
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/tag_rule"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+	"gorm.io/gorm"
+)
+
+// OrderTagRuleDelete implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) OrderTagRuleDelete(
+	ctx context.Context,
+	req *connect.Request[order_iface.OrderTagRuleDeleteRequest],
+) (*connect.Response[order_iface.OrderTagRuleDeleteResponse], error) {
+	var err error
+
+	res := order_iface.OrderTagRuleDeleteResponse{}
+	pay := req.Msg
+
+	indentity := o.
+		auth.
+		AuthIdentityFromHeader(req.Header())
+
+	err = indentity.Err()
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	err = o.checkTagRule(indentity, pay.TeamId, authorization_iface.Delete)
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	err = o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tag_rule.Delete(tx, uint(pay.TeamId), uint(pay.RuleId))
+	})
+
+	return connect.NewResponse(&res), err
+}
@@ -0,0 +1,78 @@
+package order
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/tag_rule"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+)
+
+func tagRuleToProto(rule *tag_rule.TagRule) *order_iface.OrderTagRule {
+	item := order_iface.OrderTagRule{
+		Id:         uint64(rule.ID),
+		TeamId:     uint64(rule.TeamID),
+		Name:       rule.Name,
+		Tag:        rule.Tag,
+		Active:     rule.Active,
+		Conditions: []*order_iface.OrderTagRuleCondition{},
+	}
+
+	for _, cond := range rule.Conditions {
+		item.Conditions = append(item.Conditions, &order_iface.OrderTagRuleCondition{
+			Field: string(cond.Field),
+			Op:    string(cond.Op),
+			Value: cond.Value,
+		})
+	}
+
+	return &item
+}
+
+func (o *orderServiceImpl) checkTagRule(identity authorization_iface.AuthIdentity, teamID uint64, action authorization_iface.Action) error {
+	return identity.HasPermission(authorization_iface.CheckPermissionGroup{
+		&tag_rule.TagRule{}: &authorization_iface.CheckPermission{
+			DomainID: uint(teamID),
+			Actions:  []authorization_iface.Action{action},
+		},
+	}).Err()
+}
+
+// OrderTagRuleList implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) OrderTagRuleList(
+	ctx context.Context,
+	req *connect.Request[order_iface.OrderTagRuleListRequest],
+) (*connect.Response[order_iface.OrderTagRuleListResponse], error) {
+	var err error
+
+	res := order_iface.OrderTagRuleListResponse{
+		Rules: []*order_iface.OrderTagRule{},
+	}
+	pay := req.Msg
+
+	indentity := o.
+		auth.
+		AuthIdentityFromHeader(req.Header())
+
+	err = indentity.Err()
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	err = o.checkTagRule(indentity, pay.TeamId, authorization_iface.Read)
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	rules, err := tag_rule.List(o.db.WithContext(ctx), uint(pay.TeamId))
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	for _, rule := range rules {
+		res.Rules = append(res.Rules, tagRuleToProto(rule))
+	}
+
+	return connect.NewResponse(&res), nil
+}
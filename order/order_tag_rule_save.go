@@ -0,0 +1,73 @@
+package order
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/tag_rule"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// OrderTagRuleSave implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) OrderTagRuleSave(
+	ctx context.Context,
+	req *connect.Request[order_iface.OrderTagRuleSaveRequest],
+) (*connect.Response[order_iface.OrderTagRuleSaveResponse], error) {
+	var err error
+
+	res := order_iface.OrderTagRuleSaveResponse{}
+	pay := req.Msg
+
+	indentity := o.
+		auth.
+		AuthIdentityFromHeader(req.Header())
+
+	agent := indentity.Identity()
+	err = indentity.Err()
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	err = o.checkTagRule(indentity, pay.TeamId, authorization_iface.Update)
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	if pay.Rule == nil {
+		return connect.NewResponse(&res), order_errors.RuleInvalid("rule kosong")
+	}
+
+	rule := tag_rule.TagRule{
+		ID:         uint(pay.Rule.Id),
+		TeamID:     uint(pay.TeamId),
+		Name:       pay.Rule.Name,
+		Tag:        pay.Rule.Tag,
+		Active:     pay.Rule.Active,
+		CreatedBy:  agent.IdentityID(),
+		Conditions: datatypes.JSONSlice[*tag_rule.RuleCondition]{},
+	}
+
+	for _, cond := range pay.Rule.Conditions {
+		rule.Conditions = append(rule.Conditions, &tag_rule.RuleCondition{
+			Field: tag_rule.RuleField(cond.Field),
+			Op:    tag_rule.RuleOp(cond.Op),
+			Value: cond.Value,
+		})
+	}
+
+	err = o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tag_rule.Save(tx, &rule, time.Now())
+	})
+
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	res.Rule = tagRuleToProto(&rule)
+	return connect.NewResponse(&res), nil
+}
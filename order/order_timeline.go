@@ -0,0 +1,129 @@
+package order
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/order_timeline"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/schema/services/access_iface/v1"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/schema/services/tracking_iface/v1"
+	"github.com/pdcgo/shared/authorization"
+	"github.com/pdcgo/shared/custom_connect"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// OrderTimeline implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) OrderTimeline(
+	ctx context.Context,
+	req *connect.Request[order_iface.OrderTimelineRequest],
+) (*connect.Response[order_iface.OrderTimelineResponse], error) {
+	var err error
+
+	source, err := custom_connect.GetRequestSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pay := req.Msg
+
+	var domainID uint
+	switch source.RequestFrom {
+	case access_iface.RequestFrom_REQUEST_FROM_ADMIN:
+		domainID = authorization.RootDomain
+	default:
+		domainID = uint(pay.TeamId)
+	}
+
+	err = o.auth.
+		AuthIdentityFromHeader(req.Header()).
+		HasPermission(authorization_iface.CheckPermissionGroup{
+			&db_models.Order{}: &authorization_iface.CheckPermission{
+				DomainID: domainID,
+				Actions:  []authorization_iface.Action{authorization_iface.Read},
+			},
+		}).
+		Err()
+
+	if err != nil {
+		return nil, err
+	}
+
+	db := o.db.WithContext(ctx)
+	result := order_iface.OrderTimelineResponse{
+		Events: []*order_iface.OrderTimelineEvent{},
+	}
+
+	ord := struct {
+		ID         uint
+		TeamID     uint
+		Receipt    string
+		ShippingID uint64
+	}{}
+	err = db.
+		Table("orders o").
+		Joins("left join inv_transactions it on it.id = o.invertory_tx_id").
+		Select([]string{
+			"o.id",
+			"o.team_id",
+			"o.receipt",
+			"it.shipping_id",
+		}).
+		Where("o.id = ?", pay.OrderId).
+		Find(&ord).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	if ord.ID == 0 {
+		return nil, order_errors.OrderNotFound(pay.OrderId, "")
+	}
+
+	if domainID != authorization.RootDomain && uint64(ord.TeamID) != pay.TeamId {
+		return nil, order_errors.WrongTeam(pay.OrderId, pay.TeamId)
+	}
+
+	events, err := order_timeline.Build(db, ord.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pay.WithTracking && ord.Receipt != "" {
+		res, err := o.trackService.TrackingGet(ctx, &connect.Request[tracking_iface.TrackingGetRequest]{
+			Msg: &tracking_iface.TrackingGetRequest{
+				Payload: &tracking_iface.TrackingPayload{
+					ShippingId: ord.ShippingID,
+					Receipt:    ord.Receipt,
+				},
+			},
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, order_timeline.TrackingEvents(res.Msg.TrackInfo)...)
+		order_timeline.Sort(events)
+	}
+
+	for _, event := range events {
+		result.Events = append(result.Events, &order_iface.OrderTimelineEvent{
+			Kind:      string(event.Kind),
+			Timestamp: timestamppb.New(event.Timestamp),
+			Title:     event.Title,
+			Action:    event.Action,
+			Desc:      event.Desc,
+			Amount:    event.Amount,
+			Source:    event.Source,
+			UserId:    uint64(event.UserID),
+			From:      string(event.From),
+		})
+	}
+
+	return connect.NewResponse(&result), nil
+}
@@ -0,0 +1,188 @@
+package order_timeline
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pdcgo/order_service/order/tag_core"
+	"github.com/pdcgo/schema/services/tracking_iface/v1"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/interfaces/identity_iface"
+	"gorm.io/gorm"
+)
+
+type EventKind string
+
+const (
+	EventStatus     EventKind = "status"
+	EventAdjustment EventKind = "adjustment"
+	EventTag        EventKind = "tag"
+	EventTracking   EventKind = "tracking"
+)
+
+// Event satu kejadian di order, Source asal perubahan (relation_from tag, source payment, tracking)
+type Event struct {
+	Kind      EventKind                `json:"kind"`
+	Timestamp time.Time                `json:"timestamp"`
+	Title     string                   `json:"title"`
+	Action    string                   `json:"action"`
+	Desc      string                   `json:"desc"`
+	Amount    float64                  `json:"amount"`
+	Source    string                   `json:"source"`
+	UserID    uint                     `json:"user_id"`
+	From      identity_iface.AgentType `json:"from"`
+}
+
+// Build gabungkan timestamp status, adjustment dan history tag order, sudah urut waktu
+func Build(tx *gorm.DB, orderID uint) ([]*Event, error) {
+	events := []*Event{}
+
+	timestamps := []*db_models.OrderTimestamp{}
+	err := tx.
+		Model(&db_models.OrderTimestamp{}).
+		Where("order_id = ?", orderID).
+		Find(&timestamps).
+		Error
+
+	if err != nil {
+		return events, err
+	}
+
+	for _, ts := range timestamps {
+		events = append(events, &Event{
+			Kind:      EventStatus,
+			Timestamp: ts.Timestamp,
+			Title:     string(ts.OrderStatus),
+			UserID:    ts.UserID,
+			From:      ts.From,
+		})
+	}
+
+	adjEvents, err := adjustmentEvents(tx, orderID)
+	if err != nil {
+		return events, err
+	}
+	events = append(events, adjEvents...)
+
+	histories, err := tag_core.History(tx, orderID)
+	if err != nil {
+		return events, err
+	}
+
+	for _, hist := range histories {
+		events = append(events, &Event{
+			Kind:      EventTag,
+			Timestamp: hist.Timestamp,
+			Title:     hist.TagName,
+			Action:    string(hist.Action),
+			Source:    hist.RelationFrom,
+			UserID:    hist.UserID,
+			From:      hist.From,
+		})
+	}
+
+	Sort(events)
+	return events, nil
+}
+
+// adjustmentEvents pakai history adjustment supaya ada actor,
+// adjustment lama yang belum punya history diambil dari tabel adjustment
+func adjustmentEvents(tx *gorm.DB, orderID uint) ([]*Event, error) {
+	events := []*Event{}
+
+	logs := []*db_models.OrderAdjustmentLog{}
+	err := tx.
+		Model(&db_models.OrderAdjustmentLog{}).
+		Where("order_id = ?", orderID).
+		Order("id asc").
+		Find(&logs).
+		Error
+
+	if err != nil {
+		return events, err
+	}
+
+	recorded := map[uint]bool{}
+	for _, hist := range logs {
+		recorded[hist.AdjID] = true
+
+		data := hist.Data.Data()
+		if data == nil {
+			continue
+		}
+
+		events = append(events, &Event{
+			Kind:      EventAdjustment,
+			Timestamp: hist.Timestamp,
+			Title:     string(data.Type),
+			Action:    string(hist.LogType),
+			Desc:      data.Desc,
+			Amount:    data.Amount,
+			Source:    data.Source.String(),
+			UserID:    hist.UserID,
+			From:      hist.From,
+		})
+	}
+
+	adjs := []*db_models.OrderAdjustment{}
+	err = tx.
+		Model(&db_models.OrderAdjustment{}).
+		Where("order_id = ?", orderID).
+		Where("deleted = ?", false).
+		Find(&adjs).
+		Error
+
+	if err != nil {
+		return events, err
+	}
+
+	for _, adj := range adjs {
+		if recorded[adj.ID] {
+			continue
+		}
+
+		events = append(events, &Event{
+			Kind:      EventAdjustment,
+			Timestamp: adj.At,
+			Title:     string(adj.Type),
+			Action:    string(db_models.AdjLogCreated),
+			Desc:      adj.Desc,
+			Amount:    adj.Amount,
+			Source:    adj.Source.String(),
+		})
+	}
+
+	return events, nil
+}
+
+// TrackingEvents history resi dari tracking service
+func TrackingEvents(info *tracking_iface.TrackInfo) []*Event {
+	events := []*Event{}
+	if info == nil {
+		return events
+	}
+
+	for _, item := range info.Histories {
+		if item.At == nil {
+			continue
+		}
+
+		events = append(events, &Event{
+			Kind:      EventTracking,
+			Timestamp: item.At.AsTime(),
+			Title:     item.Name,
+			Desc:      item.Desc,
+			Source:    string(db_models.RelationFromTracking),
+			From:      identity_iface.SystemAgent,
+		})
+	}
+
+	return events
+}
+
+// Sort urut dari kejadian paling lama
+func Sort(events []*Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+}
@@ -0,0 +1,97 @@
+package order_timeline_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pdcgo/order_service/order/order_timeline"
+	"github.com/pdcgo/order_service/order/tag_core"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/interfaces/identity_iface"
+	"github.com/pdcgo/shared/pkg/moretest"
+	"github.com/pdcgo/shared/pkg/moretest/moretest_mock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+func TestOrderTimeline(t *testing.T) {
+	var db gorm.DB
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	var migration moretest.SetupFunc = func(t *testing.T) func() error {
+		err := db.AutoMigrate(
+			&db_models.OrderTimestamp{},
+			&db_models.OrderAdjustment{},
+			&db_models.OrderAdjustmentLog{},
+			&tag_core.OrderTagHistory{},
+		)
+		assert.Nil(t, err)
+		return nil
+	}
+
+	var seed moretest.SetupFunc = func(t *testing.T) func() error {
+		timestamps := []*db_models.OrderTimestamp{
+			{OrderID: 1, UserID: 5, From: identity_iface.ApiAgent, OrderStatus: db_models.OrdCreated, Timestamp: now.Add(-72 * time.Hour)},
+			{OrderID: 1, UserID: 5, From: identity_iface.ApiAgent, OrderStatus: db_models.OrdCompleted, Timestamp: now},
+			{OrderID: 2, OrderStatus: db_models.OrdCreated, Timestamp: now},
+		}
+		err := db.Save(&timestamps).Error
+		assert.Nil(t, err)
+
+		adjustments := []*db_models.OrderAdjustment{
+			{ID: 1, OrderID: 1, Type: db_models.AdjCommision, Amount: -1000, At: now.Add(-24 * time.Hour)},
+			{ID: 2, OrderID: 1, Type: db_models.AdjOrderFund, Amount: 50000, At: now.Add(-time.Hour)},
+		}
+		err = db.Save(&adjustments).Error
+		assert.Nil(t, err)
+
+		hist := db_models.OrderAdjustmentLog{
+			AdjID:     2,
+			OrderID:   1,
+			UserID:    6,
+			From:      identity_iface.ApiAgent,
+			LogType:   db_models.AdjLogCreated,
+			Data:      datatypes.NewJSONType(adjustments[1]),
+			Timestamp: now.Add(-time.Hour),
+		}
+		err = db.Save(&hist).Error
+		assert.Nil(t, err)
+
+		tags := []*tag_core.OrderTagHistory{
+			{OrderID: 1, OrderTagID: 1, TagName: "terkirim", Action: tag_core.TagAdded, RelationFrom: "tracking", Timestamp: now.Add(-48 * time.Hour)},
+		}
+		err = db.Save(&tags).Error
+		assert.Nil(t, err)
+		return nil
+	}
+
+	moretest.Suite(t, "testing order timeline",
+		moretest.SetupListFunc{
+			moretest_mock.MockSqliteDatabase(&db),
+			migration,
+			seed,
+		},
+		func(t *testing.T) {
+			events, err := order_timeline.Build(&db, 1)
+			assert.Nil(t, err)
+			assert.Len(t, events, 5)
+
+			kinds := []order_timeline.EventKind{}
+			for _, event := range events {
+				kinds = append(kinds, event.Kind)
+			}
+			assert.Equal(t, []order_timeline.EventKind{
+				order_timeline.EventStatus,
+				order_timeline.EventTag,
+				order_timeline.EventAdjustment,
+				order_timeline.EventAdjustment,
+				order_timeline.EventStatus,
+			}, kinds)
+
+			assert.Equal(t, uint(0), events[2].UserID)
+			assert.Equal(t, uint(6), events[3].UserID)
+			assert.Equal(t, float64(50000), events[3].Amount)
+		},
+	)
+}
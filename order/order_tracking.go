@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/tag_rule"
 	"github.com/pdcgo/schema/services/order_iface/v1"
 	"github.com/pdcgo/schema/services/tracking_iface/v1"
 	"github.com/pdcgo/shared/db_models"
@@ -126,7 +127,9 @@ func (o *orderServiceImpl) OrderTracking(
 							return err
 						}
 
-						return nil
+						// tag rule dievaluasi ulang setelah status berubah
+						_, err = tag_rule.NewEngine(tx, ts.Timestamp).Evaluate([]uint{uint(data.OrderId)})
+						return err
 
 					})
 
@@ -0,0 +1,43 @@
+package order_view
+
+import (
+	"gorm.io/gorm"
+)
+
+type TagCount struct {
+	TagID  uint   `json:"tag_id"`
+	TeamID uint   `json:"team_id"`
+	Name   string `json:"name"`
+	Color  string `json:"color"`
+	Count  int64  `json:"count"`
+}
+
+// TagCounts jumlah order per tag di team, order yang dihitung mengikuti filter
+func TagCounts(tx *gorm.DB, teamID uint, filter *Filter) ([]*TagCount, error) {
+	counts := []*TagCount{}
+
+	orders := tx.
+		Session(&gorm.Session{NewDB: true}).
+		Table("orders").
+		Select("orders.id").
+		Scopes(filter.Scope(teamID))
+
+	err := tx.
+		Table("order_tag_relations r").
+		Select(
+			"t.id AS tag_id",
+			"t.team_id",
+			"t.name",
+			"t.color",
+			"COUNT(DISTINCT r.order_id) AS count",
+		).
+		Joins("JOIN order_tags t ON t.id = r.order_tag_id").
+		Where("r.order_id IN (?)", orders).
+		Where("t.archived = ?", false).
+		Group("t.id, t.team_id, t.name, t.color").
+		Order("count desc, t.name asc").
+		Find(&counts).
+		Error
+
+	return counts, err
+}
@@ -0,0 +1,126 @@
+package order_view_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pdcgo/order_service/order/order_view"
+	"github.com/pdcgo/order_service/order/tag_core"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/pkg/moretest"
+	"github.com/pdcgo/shared/pkg/moretest/moretest_mock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+func TestTagCounts(t *testing.T) {
+	var db gorm.DB
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	var migration moretest.SetupFunc = func(t *testing.T) func() error {
+		err := db.AutoMigrate(
+			&db_models.Order{},
+			&db_models.OrderTag{},
+			&db_models.OrderTagRelation{},
+			&order_view.SavedView{},
+		)
+		assert.Nil(t, err)
+
+		err = tag_core.Migrate(&db)
+		assert.Nil(t, err)
+		return nil
+	}
+
+	var seed moretest.SetupFunc = func(t *testing.T) func() error {
+		orders := []*db_models.Order{
+			{ID: 1, TeamID: 3, OrderMpID: 10, Status: db_models.OrdCompleted, CreatedAt: now.Add(-48 * time.Hour)},
+			{ID: 2, TeamID: 3, OrderMpID: 11, Status: db_models.OrdReturn, CreatedAt: now},
+			{ID: 3, TeamID: 3, OrderMpID: 10, Status: db_models.OrdReturn, CreatedAt: now},
+			{ID: 4, TeamID: 4, OrderMpID: 12, Status: db_models.OrdReturn, CreatedAt: now},
+		}
+		err := db.Save(&orders).Error
+		assert.Nil(t, err)
+
+		tags := []*tag_core.TagCatalogue{
+			{ID: 1, TeamID: 3, Name: "return_risk", Slug: "return_risk"},
+			{ID: 2, TeamID: 0, Name: "terkirim", Slug: "terkirim"},
+		}
+		err = db.Save(&tags).Error
+		assert.Nil(t, err)
+
+		relations := []*db_models.OrderTagRelation{
+			{OrderID: 1, OrderTagID: 1, RelationFrom: "user"},
+			{OrderID: 2, OrderTagID: 1, RelationFrom: "user"},
+			{OrderID: 2, OrderTagID: 2, RelationFrom: "tracking"},
+			{OrderID: 3, OrderTagID: 2, RelationFrom: "tracking"},
+			{OrderID: 4, OrderTagID: 2, RelationFrom: "tracking"},
+		}
+		err = db.Save(&relations).Error
+		assert.Nil(t, err)
+		return nil
+	}
+
+	moretest.Suite(t, "testing tag counts",
+		moretest.SetupListFunc{
+			moretest_mock.MockSqliteDatabase(&db),
+			migration,
+			seed,
+		},
+		func(t *testing.T) {
+			t.Run("hitung per tag di team", func(t *testing.T) {
+				counts, err := order_view.TagCounts(&db, 3, &order_view.Filter{})
+				assert.Nil(t, err)
+				assert.Len(t, counts, 2)
+
+				for _, count := range counts {
+					assert.Equal(t, int64(2), count.Count)
+				}
+			})
+
+			t.Run("filter status dan tanggal", func(t *testing.T) {
+				from := now.Add(-time.Hour)
+				counts, err := order_view.TagCounts(&db, 3, &order_view.Filter{
+					Statuses: []db_models.OrdStatus{db_models.OrdReturn},
+					From:     &from,
+				})
+				assert.Nil(t, err)
+				assert.Len(t, counts, 2)
+				assert.Equal(t, "terkirim", counts[0].Name)
+				assert.Equal(t, int64(2), counts[0].Count)
+				assert.Equal(t, int64(1), counts[1].Count)
+			})
+
+			t.Run("view dipakai query order", func(t *testing.T) {
+				view := order_view.SavedView{
+					TeamID: 3,
+					UserID: 7,
+					Name:   "retur toko 10",
+					Filter: datatypes.NewJSONType(order_view.Filter{
+						TagIDs:  []uint{2},
+						ShopIDs: []uint{10},
+					}),
+				}
+				err := order_view.Save(&db, &view, now)
+				assert.Nil(t, err)
+
+				saved, err := order_view.Get(&db, 3, 7, view.ID)
+				assert.Nil(t, err)
+
+				filter := saved.Filter.Data()
+				ids := []uint{}
+				err = db.
+					Model(&db_models.Order{}).
+					Scopes(filter.Scope(3)).
+					Pluck("orders.id", &ids).
+					Error
+				assert.Nil(t, err)
+				assert.Equal(t, []uint{3}, ids)
+
+				_, err = order_view.Get(&db, 3, 8, view.ID)
+				assert.ErrorIs(t, err, order_errors.ErrViewNotFound)
+			})
+		},
+	)
+}
@@ -0,0 +1,196 @@
+package order_view
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/shared/db_models"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Filter filter order yang disimpan di view, dipakai juga query list order
+type Filter struct {
+	TagIDs   []uint                `json:"tag_ids"`
+	Statuses []db_models.OrdStatus `json:"statuses"`
+	ShopIDs  []uint                `json:"shop_ids"`
+	// filter waktu order dibuat, tidak disimpan di view
+	From *time.Time `json:"-"`
+	To   *time.Time `json:"-"`
+}
+
+// Scope filter untuk query tabel orders, order harus punya semua tag di TagIDs
+func (f *Filter) Scope(teamID uint) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		tx = tx.Where("orders.team_id = ?", teamID)
+
+		if len(f.Statuses) != 0 {
+			tx = tx.Where("orders.status IN ?", f.Statuses)
+		}
+
+		if len(f.ShopIDs) != 0 {
+			tx = tx.Where("orders.order_mp_id IN ?", f.ShopIDs)
+		}
+
+		if f.From != nil {
+			tx = tx.Where("orders.created_at >= ?", *f.From)
+		}
+
+		if f.To != nil {
+			tx = tx.Where("orders.created_at < ?", *f.To)
+		}
+
+		for _, tagID := range f.TagIDs {
+			tx = tx.Where(
+				"EXISTS (SELECT 1 FROM order_tag_relations otr WHERE otr.order_id = orders.id AND otr.order_tag_id = ?)",
+				tagID,
+			)
+		}
+
+		return tx
+	}
+}
+
+// SavedView filter order bernama milik user di team
+type SavedView struct {
+	ID      uint                       `json:"id" gorm:"primarykey"`
+	TeamID  uint                       `json:"team_id" gorm:"index"`
+	UserID  uint                       `json:"user_id" gorm:"index"`
+	Name    string                     `json:"name"`
+	Filter  datatypes.JSONType[Filter] `json:"filter"`
+	Created time.Time                  `json:"created"`
+	Updated time.Time                  `json:"updated"`
+}
+
+func (SavedView) TableName() string {
+	return "order_saved_views"
+}
+
+func List(tx *gorm.DB, teamID uint, userID uint) ([]*SavedView, error) {
+	views := []*SavedView{}
+	err := tx.
+		Model(&SavedView{}).
+		Where("team_id = ?", teamID).
+		Where("user_id = ?", userID).
+		Order("name asc").
+		Find(&views).
+		Error
+
+	return views, err
+}
+
+func Get(tx *gorm.DB, teamID uint, userID uint, viewID uint) (*SavedView, error) {
+	var view SavedView
+	err := tx.
+		Model(&SavedView{}).
+		Where("id = ?", viewID).
+		Where("team_id = ?", teamID).
+		Where("user_id = ?", userID).
+		Find(&view).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	if view.ID == 0 {
+		return nil, order_errors.ViewNotFound(uint64(viewID))
+	}
+
+	return &view, nil
+}
+
+// Save buat view baru atau ubah view milik user
+func Save(tx *gorm.DB, view *SavedView, now time.Time) error {
+	view.Name = strings.TrimSpace(view.Name)
+	if view.Name == "" {
+		return order_errors.ViewEmptyName()
+	}
+
+	view.Updated = now
+	if view.ID == 0 {
+		view.Created = now
+		return tx.Create(view).Error
+	}
+
+	old, err := Get(tx, view.TeamID, view.UserID, view.ID)
+	if err != nil {
+		return err
+	}
+
+	view.Created = old.Created
+	return tx.Save(view).Error
+}
+
+// ReplaceTags tag yang digabung diganti ke tag tujuan di filter view.
+// teamID 0 berarti tag sistem, view semua team ikut dicek
+func ReplaceTags(tx *gorm.DB, teamID uint, targetID uint, sourceIDs []uint) error {
+	// saat migrasi tag pertama kali tabel view belum dibuat
+	if len(sourceIDs) == 0 || !tx.Migrator().HasTable(&SavedView{}) {
+		return nil
+	}
+
+	sources := map[uint]bool{}
+	for _, id := range sourceIDs {
+		sources[id] = true
+	}
+
+	query := tx.Model(&SavedView{})
+	if teamID != 0 {
+		query = query.Where("team_id = ?", teamID)
+	}
+
+	views := []*SavedView{}
+	return query.
+		FindInBatches(&views, 200, func(tx *gorm.DB, batch int) error {
+			for _, view := range views {
+				filter := view.Filter.Data()
+
+				changed := false
+				seen := map[uint]bool{}
+				tagIDs := []uint{}
+				for _, id := range filter.TagIDs {
+					if sources[id] {
+						id = targetID
+						changed = true
+					}
+
+					if seen[id] {
+						continue
+					}
+					seen[id] = true
+					tagIDs = append(tagIDs, id)
+				}
+
+				if !changed {
+					continue
+				}
+
+				filter.TagIDs = tagIDs
+				err := tx.
+					Model(&SavedView{}).
+					Where("id = ?", view.ID).
+					Update("filter", datatypes.NewJSONType(filter)).
+					Error
+
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}).
+		Error
+}
+
+func Delete(tx *gorm.DB, teamID uint, userID uint, viewID uint) error {
+	view, err := Get(tx, teamID, userID, viewID)
+	if err != nil {
+		return err
+	}
+
+	return tx.
+		Where("id = ?", view.ID).
+		Delete(&SavedView{}).
+		Error
+}
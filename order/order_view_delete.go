@@ -0,0 +1,41 @@
+package order
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/order_view"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+)
+
+// OrderViewDelete implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) OrderViewDelete(
+	ctx context.Context,
+	req *connect.Request[order_iface.OrderViewDeleteRequest],
+) (*connect.Response[order_iface.OrderViewDeleteResponse], error) {
+	var err error
+
+	res := order_iface.OrderViewDeleteResponse{}
+	pay := req.Msg
+
+	indentity := o.
+		auth.
+		AuthIdentityFromHeader(req.Header())
+
+	agent := indentity.Identity()
+	err = indentity.HasPermission(authorization_iface.CheckPermissionGroup{
+		&db_models.Order{}: &authorization_iface.CheckPermission{
+			DomainID: uint(pay.TeamId),
+			Actions:  []authorization_iface.Action{authorization_iface.Read},
+		},
+	}).Err()
+
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	err = order_view.Delete(o.db.WithContext(ctx), uint(pay.TeamId), agent.IdentityID(), uint(pay.ViewId))
+	return connect.NewResponse(&res), err
+}
@@ -0,0 +1,74 @@
+package order
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/order_view"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+)
+
+func orderViewToProto(view *order_view.SavedView) *order_iface.OrderView {
+	filter := view.Filter.Data()
+	item := order_iface.OrderView{
+		Id:       uint64(view.ID),
+		Name:     view.Name,
+		TagIds:   []uint64{},
+		Statuses: []string{},
+		ShopIds:  []uint64{},
+	}
+
+	for _, id := range filter.TagIDs {
+		item.TagIds = append(item.TagIds, uint64(id))
+	}
+	for _, status := range filter.Statuses {
+		item.Statuses = append(item.Statuses, string(status))
+	}
+	for _, id := range filter.ShopIDs {
+		item.ShopIds = append(item.ShopIds, uint64(id))
+	}
+
+	return &item
+}
+
+// OrderViewList implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) OrderViewList(
+	ctx context.Context,
+	req *connect.Request[order_iface.OrderViewListRequest],
+) (*connect.Response[order_iface.OrderViewListResponse], error) {
+	var err error
+
+	res := order_iface.OrderViewListResponse{
+		Views: []*order_iface.OrderView{},
+	}
+	pay := req.Msg
+
+	indentity := o.
+		auth.
+		AuthIdentityFromHeader(req.Header())
+
+	agent := indentity.Identity()
+	err = indentity.HasPermission(authorization_iface.CheckPermissionGroup{
+		&db_models.Order{}: &authorization_iface.CheckPermission{
+			DomainID: uint(pay.TeamId),
+			Actions:  []authorization_iface.Action{authorization_iface.Read},
+		},
+	}).Err()
+
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	views, err := order_view.List(o.db.WithContext(ctx), uint(pay.TeamId), agent.IdentityID())
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	for _, view := range views {
+		res.Views = append(res.Views, orderViewToProto(view))
+	}
+
+	return connect.NewResponse(&res), nil
+}
@@ -0,0 +1,85 @@
+package order
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order/order_view"
+	"github.com/pdcgo/order_service/order/tag_core"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/interfaces/authorization_iface"
+	"gorm.io/datatypes"
+)
+
+// OrderViewSave implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceImpl) OrderViewSave(
+	ctx context.Context,
+	req *connect.Request[order_iface.OrderViewSaveRequest],
+) (*connect.Response[order_iface.OrderViewSaveResponse], error) {
+	var err error
+
+	res := order_iface.OrderViewSaveResponse{}
+	pay := req.Msg
+
+	indentity := o.
+		auth.
+		AuthIdentityFromHeader(req.Header())
+
+	agent := indentity.Identity()
+	err = indentity.HasPermission(authorization_iface.CheckPermissionGroup{
+		&db_models.Order{}: &authorization_iface.CheckPermission{
+			DomainID: uint(pay.TeamId),
+			Actions:  []authorization_iface.Action{authorization_iface.Read},
+		},
+	}).Err()
+
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	if pay.View == nil {
+		return connect.NewResponse(&res), order_errors.ViewEmptyName()
+	}
+
+	filter := order_view.Filter{
+		TagIDs:   []uint{},
+		Statuses: []db_models.OrdStatus{},
+		ShopIDs:  []uint{},
+	}
+	db := o.db.WithContext(ctx)
+
+	// tag harus milik team atau tag sistem
+	for _, id := range pay.View.TagIds {
+		_, err = tag_core.Get(db, uint(pay.TeamId), uint(id))
+		if err != nil {
+			return connect.NewResponse(&res), err
+		}
+
+		filter.TagIDs = append(filter.TagIDs, uint(id))
+	}
+	for _, status := range pay.View.Statuses {
+		filter.Statuses = append(filter.Statuses, db_models.OrdStatus(status))
+	}
+	for _, id := range pay.View.ShopIds {
+		filter.ShopIDs = append(filter.ShopIDs, uint(id))
+	}
+
+	view := order_view.SavedView{
+		ID:     uint(pay.View.Id),
+		TeamID: uint(pay.TeamId),
+		UserID: agent.IdentityID(),
+		Name:   pay.View.Name,
+		Filter: datatypes.NewJSONType(filter),
+	}
+
+	err = order_view.Save(db, &view, time.Now())
+	if err != nil {
+		return connect.NewResponse(&res), err
+	}
+
+	res.View = orderViewToProto(&view)
+	return connect.NewResponse(&res), nil
+}
@@ -0,0 +1,392 @@
+package tag_core
+
+import (
+	"github.com/pdcgo/order_service/order/order_view"
+	"github.com/pdcgo/order_service/order_errors"
+	"gorm.io/gorm"
+)
+
+func validateName(name string) error {
+	if name == "" || Slug(name) == "" {
+		return order_errors.TagEmptyName()
+	}
+	if len(name) > MaxNameLength {
+		return order_errors.TagTooLong(name, MaxNameLength)
+	}
+	return nil
+}
+
+// findBySlug cari di tag team dan tag sistem
+func findBySlug(tx *gorm.DB, teamID uint, slug string) (*TagCatalogue, error) {
+	var tag TagCatalogue
+	err := tx.
+		Model(&TagCatalogue{}).
+		Scopes(Scope(teamID)).
+		Where("slug = ?", slug).
+		Order("team_id asc, id asc").
+		Limit(1).
+		Find(&tag).
+		Error
+
+	return &tag, err
+}
+
+// Get tag yang terlihat oleh team
+func Get(tx *gorm.DB, teamID uint, tagID uint) (*TagCatalogue, error) {
+	var tag TagCatalogue
+	err := tx.
+		Model(&TagCatalogue{}).
+		Scopes(Scope(teamID)).
+		Where("id = ?", tagID).
+		Find(&tag).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	if tag.ID == 0 {
+		return nil, order_errors.TagNotFound(uint64(tagID))
+	}
+
+	return &tag, nil
+}
+
+// getOwned tag yang boleh diubah team, tag sistem hanya dari SystemTeamID
+func getOwned(tx *gorm.DB, teamID uint, tagID uint) (*TagCatalogue, error) {
+	tag, err := Get(tx, teamID, tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tag.TeamID != teamID {
+		return nil, order_errors.TagNotFound(uint64(tagID))
+	}
+
+	return tag, nil
+}
+
+type TagData struct {
+	Name        string
+	Color       string
+	Description string
+}
+
+func Create(tx *gorm.DB, teamID uint, data *TagData) (*TagCatalogue, error) {
+	name := NormalizeName(data.Name)
+	err := validateName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ValidColor(data.Color) {
+		return nil, order_errors.TagInvalidColor(data.Color)
+	}
+
+	existing, err := findBySlug(tx, teamID, Slug(name))
+	if err != nil {
+		return nil, err
+	}
+
+	if existing.ID != 0 {
+		return nil, order_errors.TagDuplicate(uint64(existing.ID), existing.Name)
+	}
+
+	tag := TagCatalogue{
+		TeamID:      teamID,
+		Name:        name,
+		Slug:        Slug(name),
+		Color:       data.Color,
+		Description: data.Description,
+	}
+
+	err = tx.Create(&tag).Error
+	return &tag, err
+}
+
+// Rename ubah nama dan metadata, field kosong tidak diubah
+func Rename(tx *gorm.DB, teamID uint, tagID uint, data *TagData) (*TagCatalogue, error) {
+	tag, err := getOwned(tx, teamID, tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Name != "" {
+		name := NormalizeName(data.Name)
+		err = validateName(name)
+		if err != nil {
+			return nil, err
+		}
+
+		existing, err := findBySlug(tx, teamID, Slug(name))
+		if err != nil {
+			return nil, err
+		}
+
+		if existing.ID != 0 && existing.ID != tag.ID {
+			return nil, order_errors.TagDuplicate(uint64(existing.ID), existing.Name)
+		}
+
+		tag.Name = name
+		tag.Slug = Slug(name)
+	}
+
+	if data.Color != "" {
+		if !ValidColor(data.Color) {
+			return nil, order_errors.TagInvalidColor(data.Color)
+		}
+		tag.Color = data.Color
+	}
+
+	if data.Description != "" {
+		tag.Description = data.Description
+	}
+
+	err = tx.
+		Model(&TagCatalogue{}).
+		Where("id = ?", tag.ID).
+		Updates(map[string]interface{}{
+			"name":        tag.Name,
+			"slug":        tag.Slug,
+			"color":       tag.Color,
+			"description": tag.Description,
+		}).
+		Error
+
+	return tag, err
+}
+
+func Archive(tx *gorm.DB, teamID uint, tagID uint, archived bool) error {
+	tag, err := getOwned(tx, teamID, tagID)
+	if err != nil {
+		return err
+	}
+
+	return tx.
+		Model(&TagCatalogue{}).
+		Where("id = ?", tag.ID).
+		Update("archived", archived).
+		Error
+}
+
+// Merge pindahkan relasi order dari tag sumber ke target lalu hapus tag sumber.
+// tag sumber harus milik team, target boleh tag sistem. harus dipanggil di dalam transaksi
+func Merge(tx *gorm.DB, actor *TagActor, teamID uint, targetID uint, sourceIDs []uint) (int64, error) {
+	_, err := Get(tx, teamID, targetID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, sourceID := range sourceIDs {
+		if sourceID == targetID {
+			return 0, order_errors.TagMergeSelf(uint64(sourceID))
+		}
+
+		_, err = getOwned(tx, teamID, sourceID)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return mergeInto(tx, actor, teamID, targetID, sourceIDs)
+}
+
+// mergeInto pindahkan relasi dan filter saved view ke tag tujuan lalu hapus tag sumber
+func mergeInto(tx *gorm.DB, actor *TagActor, teamID uint, targetID uint, sourceIDs []uint) (int64, error) {
+	if len(sourceIDs) == 0 {
+		return 0, nil
+	}
+
+	moved, err := moveRelations(tx, actor, targetID, func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("order_tag_id IN ?", sourceIDs)
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	err = order_view.ReplaceTags(tx, teamID, targetID, sourceIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.
+		Where("id IN ?", sourceIDs).
+		Delete(&TagCatalogue{}).
+		Error
+
+	return moved, err
+}
+
+type ListFilter struct {
+	TeamID          uint
+	Q               string
+	IncludeArchived bool
+}
+
+func List(tx *gorm.DB, filter *ListFilter) ([]*TagCatalogue, error) {
+	tags := []*TagCatalogue{}
+
+	query := tx.
+		Model(&TagCatalogue{}).
+		Scopes(Scope(filter.TeamID)).
+		Order("name asc")
+
+	if !filter.IncludeArchived {
+		query = query.Where("archived = ?", false)
+	}
+
+	if filter.Q != "" {
+		query = query.Where("slug LIKE ?", "%"+Slug(filter.Q)+"%")
+	}
+
+	err := query.Find(&tags).Error
+	return tags, err
+}
+
+// normalizeNames nama tag jadi slug unik, urutan input dipertahankan
+func normalizeNames(names []string) ([]string, map[string]string, error) {
+	slugs := []string{}
+	bySlug := map[string]string{}
+
+	for _, name := range names {
+		name = NormalizeName(name)
+		err := validateName(name)
+		if err != nil {
+			return slugs, bySlug, err
+		}
+
+		slug := Slug(name)
+		if _, ok := bySlug[slug]; ok {
+			continue
+		}
+
+		bySlug[slug] = name
+		slugs = append(slugs, slug)
+	}
+
+	return slugs, bySlug, nil
+}
+
+// lookup id tag per slug yang terlihat oleh team, tag sistem didahulukan
+func lookup(tx *gorm.DB, teamID uint, slugs []string) (map[string]uint, error) {
+	found := map[string]uint{}
+
+	tags := []*TagCatalogue{}
+	err := tx.
+		Model(&TagCatalogue{}).
+		Scopes(Scope(teamID)).
+		Where("slug IN ?", slugs).
+		Order("team_id asc, id asc").
+		Find(&tags).
+		Error
+
+	if err != nil {
+		return found, err
+	}
+
+	for _, tag := range tags {
+		if _, ok := found[tag.Slug]; !ok {
+			found[tag.Slug] = tag.ID
+		}
+	}
+
+	return found, nil
+}
+
+// Lookup id tag yang sudah ada, nama yang tidak ditemukan dilewati
+func Lookup(tx *gorm.DB, teamID uint, names []string) ([]uint, error) {
+	ids := []uint{}
+	slugs, _, err := normalizeNames(names)
+	if err != nil {
+		return ids, err
+	}
+
+	found, err := lookup(tx, teamID, slugs)
+	if err != nil {
+		return ids, err
+	}
+
+	// tag yang diarsip dipakai lagi, arsipnya dibuka supaya muncul di list
+	foundIDs := []uint{}
+	for _, id := range found {
+		foundIDs = append(foundIDs, id)
+	}
+
+	if len(foundIDs) != 0 {
+		err = tx.
+			Model(&TagCatalogue{}).
+			Where("id IN ?", foundIDs).
+			Where("archived = ?", true).
+			Update("archived", false).
+			Error
+
+		if err != nil {
+			return ids, err
+		}
+	}
+
+	for _, slug := range slugs {
+		if id, ok := found[slug]; ok {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+// FindOrCreate id tag berdasarkan slug, tag yang belum ada dibuat di team.
+// dipakai tag mutation supaya tidak ada tag kembar beda huruf besar atau spasi
+func FindOrCreate(tx *gorm.DB, teamID uint, names []string) ([]uint, error) {
+	ids := []uint{}
+	slugs, bySlug, err := normalizeNames(names)
+	if err != nil {
+		return ids, err
+	}
+
+	found, err := lookup(tx, teamID, slugs)
+	if err != nil {
+		return ids, err
+	}
+
+	// tag yang diarsip dipakai lagi, arsipnya dibuka supaya muncul di list
+	foundIDs := []uint{}
+	for _, id := range found {
+		foundIDs = append(foundIDs, id)
+	}
+
+	if len(foundIDs) != 0 {
+		err = tx.
+			Model(&TagCatalogue{}).
+			Where("id IN ?", foundIDs).
+			Where("archived = ?", true).
+			Update("archived", false).
+			Error
+
+		if err != nil {
+			return ids, err
+		}
+	}
+
+	for _, slug := range slugs {
+		if id, ok := found[slug]; ok {
+			ids = append(ids, id)
+			continue
+		}
+
+		tag := TagCatalogue{
+			TeamID: teamID,
+			Name:   bySlug[slug],
+			Slug:   slug,
+		}
+
+		err = tx.Create(&tag).Error
+		if err != nil {
+			return ids, err
+		}
+
+		ids = append(ids, tag.ID)
+	}
+
+	return ids, nil
+}
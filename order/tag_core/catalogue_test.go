@@ -0,0 +1,144 @@
+package tag_core_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pdcgo/order_service/order/order_view"
+	"github.com/pdcgo/order_service/order/tag_core"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/pkg/moretest"
+	"github.com/pdcgo/shared/pkg/moretest/moretest_mock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+func TestTagCatalogue(t *testing.T) {
+	var db gorm.DB
+
+	var migration moretest.SetupFunc = func(t *testing.T) func() error {
+		err := db.AutoMigrate(
+			&db_models.Order{},
+			&db_models.OrderTag{},
+			&db_models.OrderTagRelation{},
+			&order_view.SavedView{},
+		)
+		assert.Nil(t, err)
+
+		err = tag_core.Migrate(&db)
+		assert.Nil(t, err)
+		return nil
+	}
+
+	moretest.Suite(t, "testing tag catalogue",
+		moretest.SetupListFunc{
+			moretest_mock.MockSqliteDatabase(&db),
+			migration,
+		},
+		func(t *testing.T) {
+			t.Run("slug case-fold", func(t *testing.T) {
+				assert.Equal(t, "ditolak_pembeli", tag_core.Slug("  Ditolak   Pembeli "))
+				assert.Equal(t, "ditolak_pembeli", tag_core.Slug("ditolak_pembeli"))
+			})
+
+			t.Run("find or create tidak buat tag kembar", func(t *testing.T) {
+				ids, err := tag_core.FindOrCreate(&db, 0, []string{"Selesai", " selesai ", "return"})
+				assert.Nil(t, err)
+				assert.Len(t, ids, 2)
+
+				again, err := tag_core.FindOrCreate(&db, 0, []string{"SELESAI"})
+				assert.Nil(t, err)
+				assert.Equal(t, ids[0], again[0])
+			})
+
+			t.Run("create duplikat", func(t *testing.T) {
+				_, err := tag_core.Create(&db, 0, &tag_core.TagData{Name: "selesai"})
+				assert.True(t, errors.Is(err, order_errors.ErrTagDuplicate))
+
+				_, err = tag_core.Create(&db, 0, &tag_core.TagData{Name: "retur", Color: "merah"})
+				assert.ErrorIs(t, err, order_errors.ErrTagInvalidColor)
+			})
+
+			t.Run("merge pindahkan relasi", func(t *testing.T) {
+				target, err := tag_core.Create(&db, 0, &tag_core.TagData{Name: "Retur", Color: "#ff0000"})
+				assert.Nil(t, err)
+
+				ids, err := tag_core.FindOrCreate(&db, 0, []string{"return"})
+				assert.Nil(t, err)
+				source := ids[0]
+
+				relations := []*db_models.OrderTagRelation{
+					{OrderID: 1, OrderTagID: source, RelationFrom: "tracking"},
+					{OrderID: 2, OrderTagID: source, RelationFrom: "tracking"},
+					{OrderID: 2, OrderTagID: target.ID, RelationFrom: "user"},
+				}
+				err = db.Save(&relations).Error
+				assert.Nil(t, err)
+
+				view := order_view.SavedView{
+					TeamID: 3,
+					UserID: 7,
+					Name:   "retur",
+					Filter: datatypes.NewJSONType(order_view.Filter{TagIDs: []uint{source, target.ID}}),
+				}
+				err = db.Save(&view).Error
+				assert.Nil(t, err)
+
+				err = db.Transaction(func(tx *gorm.DB) error {
+					_, err := tag_core.Merge(tx, nil, 0, target.ID, []uint{source})
+					return err
+				})
+				assert.Nil(t, err)
+
+				var count int64
+				err = db.Model(&db_models.OrderTagRelation{}).Where("order_tag_id = ?", target.ID).Count(&count).Error
+				assert.Nil(t, err)
+				assert.Equal(t, int64(2), count)
+
+				_, err = tag_core.Get(&db, 0, source)
+				assert.True(t, errors.Is(err, order_errors.ErrTagNotFound))
+
+				saved, err := order_view.Get(&db, 3, 7, view.ID)
+				assert.Nil(t, err)
+				assert.Equal(t, []uint{target.ID}, saved.Filter.Data().TagIDs)
+
+				var removed int64
+				err = db.Model(&tag_core.OrderTagHistory{}).
+					Where("order_tag_id = ? AND action = ?", source, tag_core.TagRemoved).
+					Count(&removed).
+					Error
+				assert.Nil(t, err)
+				assert.NotZero(t, removed)
+			})
+
+			t.Run("archive tidak muncul di list", func(t *testing.T) {
+				ids, err := tag_core.FindOrCreate(&db, 0, []string{"selesai"})
+				assert.Nil(t, err)
+
+				err = tag_core.Archive(&db, 0, ids[0], true)
+				assert.Nil(t, err)
+
+				tags, err := tag_core.List(&db, &tag_core.ListFilter{})
+				assert.Nil(t, err)
+				for _, tag := range tags {
+					assert.NotEqual(t, ids[0], tag.ID)
+				}
+
+				tags, err = tag_core.List(&db, &tag_core.ListFilter{Q: "Sele", IncludeArchived: true})
+				assert.Nil(t, err)
+				assert.Len(t, tags, 1)
+
+				// dipakai lagi lewat find or create, arsip dibuka
+				again, err := tag_core.FindOrCreate(&db, 0, []string{"selesai"})
+				assert.Nil(t, err)
+				assert.Equal(t, ids, again)
+
+				tag, err := tag_core.Get(&db, 0, ids[0])
+				assert.Nil(t, err)
+				assert.False(t, tag.Archived)
+			})
+		},
+	)
+}
@@ -0,0 +1,134 @@
+package tag_core
+
+import (
+	"time"
+
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/interfaces/identity_iface"
+	"gorm.io/gorm"
+)
+
+type TagAction string
+
+const (
+	TagAdded   TagAction = "add"
+	TagRemoved TagAction = "remove"
+)
+
+// OrderTagHistory append only, nama tag disimpan karena tag bisa dihapus atau digabung
+type OrderTagHistory struct {
+	ID           uint                     `json:"id" gorm:"primarykey"`
+	OrderID      uint                     `json:"order_id" gorm:"index"`
+	OrderTagID   uint                     `json:"order_tag_id" gorm:"index"`
+	TagName      string                   `json:"tag_name"`
+	Action       TagAction                `json:"action"`
+	RelationFrom string                   `json:"relation_from"`
+	UserID       uint                     `json:"user_id"`
+	From         identity_iface.AgentType `json:"from"`
+	Timestamp    time.Time                `json:"timestamp" gorm:"index"`
+}
+
+// TagActor siapa yang mengubah tag order
+type TagActor struct {
+	UserID uint
+	From   identity_iface.AgentType
+}
+
+// SystemActor dipakai kalau pemanggil tidak mengirim actor, misal service tracking
+var SystemActor = &TagActor{
+	From: identity_iface.SystemAgent,
+}
+
+// RecordHistory catat relasi yang baru ditambah atau dihapus
+func RecordHistory(tx *gorm.DB, actor *TagActor, action TagAction, relations []*db_models.OrderTagRelation) error {
+	if len(relations) == 0 {
+		return nil
+	}
+
+	if actor == nil {
+		actor = SystemActor
+	}
+
+	tagIDs := []uint{}
+	for _, rel := range relations {
+		tagIDs = append(tagIDs, rel.OrderTagID)
+	}
+
+	tags := []*TagCatalogue{}
+	err := tx.
+		Model(&TagCatalogue{}).
+		Select("id", "name").
+		Where("id IN ?", tagIDs).
+		Find(&tags).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	names := map[uint]string{}
+	for _, tag := range tags {
+		names[tag.ID] = tag.Name
+	}
+
+	now := time.Now()
+	histories := make([]*OrderTagHistory, len(relations))
+	for i, rel := range relations {
+		histories[i] = &OrderTagHistory{
+			OrderID:      rel.OrderID,
+			OrderTagID:   rel.OrderTagID,
+			TagName:      names[rel.OrderTagID],
+			Action:       action,
+			RelationFrom: rel.RelationFrom,
+			UserID:       actor.UserID,
+			From:         actor.From,
+			Timestamp:    now,
+		}
+	}
+
+	return tx.CreateInBatches(&histories, 500).Error
+}
+
+// RemoveRelations hapus relasi hasil scope dan catat di history
+func RemoveRelations(tx *gorm.DB, actor *TagActor, scope func(tx *gorm.DB) *gorm.DB) error {
+	relations := []*db_models.OrderTagRelation{}
+	err := tx.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&db_models.OrderTagRelation{}).
+		Scopes(scope).
+		Find(&relations).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	if len(relations) == 0 {
+		return nil
+	}
+
+	err = tx.
+		Session(&gorm.Session{NewDB: true}).
+		Scopes(scope).
+		Delete(&db_models.OrderTagRelation{}).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	return RecordHistory(tx, actor, TagRemoved, relations)
+}
+
+// History riwayat tag satu order, terbaru di atas
+func History(tx *gorm.DB, orderID uint) ([]*OrderTagHistory, error) {
+	histories := []*OrderTagHistory{}
+	err := tx.
+		Model(&OrderTagHistory{}).
+		Where("order_id = ?", orderID).
+		Order("timestamp desc, id desc").
+		Find(&histories).
+		Error
+
+	return histories, err
+}
@@ -0,0 +1,170 @@
+package tag_core
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pdcgo/shared/db_models"
+	"gorm.io/gorm"
+)
+
+// TagCatalogue kolom tambahan tabel order_tags (model OrderTag di shared hanya id dan name)
+type TagCatalogue struct {
+	ID          uint   `json:"id" gorm:"primarykey"`
+	TeamID      uint   `json:"team_id" gorm:"default:0;index:order_tag_team_slug,unique,priority:1"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug" gorm:"index:idx_order_tags_slug;index:order_tag_team_slug,unique,priority:2"`
+	Color       string `json:"color" gorm:"size:16"`
+	Description string `json:"description"`
+	Archived    bool   `json:"archived" gorm:"index"`
+}
+
+func (TagCatalogue) TableName() string {
+	return "order_tags"
+}
+
+func (t *TagCatalogue) GetEntityID() string {
+	return "order_tag"
+}
+
+// batas panjang nama tag
+const MaxNameLength = 300
+
+// SystemTeamID tag sistem (tracking, warehouse) terlihat di semua team
+const SystemTeamID uint = 0
+
+// IsSystemFrom relasi dari service lain memakai tag sistem, selain itu tag milik team order
+func IsSystemFrom(from db_models.RelationFrom) bool {
+	switch from {
+	case db_models.RelationFromTracking, db_models.RelationFromWarehouse:
+		return true
+	}
+	return false
+}
+
+// Scope tag yang terlihat oleh team, tag team sendiri dan tag sistem
+func Scope(teamID uint) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("order_tags.team_id IN ?", []uint{SystemTeamID, teamID})
+	}
+}
+
+var (
+	spaces  = regexp.MustCompile(`\s+`)
+	nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+	colorRe = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+)
+
+// NormalizeName rapikan spasi, "selesai " dan "selesai" dianggap sama
+func NormalizeName(name string) string {
+	return spaces.ReplaceAllString(strings.TrimSpace(name), " ")
+}
+
+// Slug kunci unik tag, case-fold dan karakter selain huruf angka jadi underscore.
+// "Ditolak Pembeli" dan "ditolak_pembeli" punya slug yang sama
+func Slug(name string) string {
+	slug := nonSlug.ReplaceAllString(strings.ToLower(NormalizeName(name)), "_")
+	return strings.Trim(slug, "_")
+}
+
+func ValidColor(color string) bool {
+	return color == "" || colorRe.MatchString(color)
+}
+
+// Migrate tambah kolom catalogue, isi slug tag lama dan pecah tag lama per team.
+// semua langkah dalam satu transaction, kalau gagal kolom team_id ikut batal
+// sehingga split dijalankan lagi saat migrate berikutnya
+func Migrate(db *gorm.DB) error {
+	return db.Transaction(migrateCatalogue)
+}
+
+func migrateCatalogue(tx *gorm.DB) error {
+	// tag dipecah hanya sekali, saat kolom team_id baru ditambahkan
+	split := !tx.Migrator().HasColumn(&TagCatalogue{}, "team_id")
+
+	// relasi yang dipindah saat split dan dedupe dicatat di history
+	err := tx.AutoMigrate(&OrderTagHistory{})
+	if err != nil {
+		return err
+	}
+
+	columns := map[string]string{
+		"team_id":     "TeamID",
+		"slug":        "Slug",
+		"color":       "Color",
+		"description": "Description",
+		"archived":    "Archived",
+	}
+
+	for column, field := range columns {
+		if tx.Migrator().HasColumn(&TagCatalogue{}, column) {
+			continue
+		}
+
+		err := tx.Migrator().AddColumn(&TagCatalogue{}, field)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, field := range []string{"idx_order_tags_slug", "Archived"} {
+		if tx.Migrator().HasIndex(&TagCatalogue{}, field) {
+			continue
+		}
+
+		err := tx.Migrator().CreateIndex(&TagCatalogue{}, field)
+		if err != nil {
+			return err
+		}
+	}
+
+	// nama tag sekarang unik per team, bukan global. ikut batal kalau split gagal
+	if tx.Migrator().HasIndex(&db_models.OrderTag{}, "order_tag_unique") {
+		err := tx.Migrator().DropIndex(&db_models.OrderTag{}, "order_tag_unique")
+		if err != nil {
+			return err
+		}
+	}
+
+	tags := []*TagCatalogue{}
+	err = tx.
+		Model(&TagCatalogue{}).
+		Where("slug IS NULL OR slug = ''").
+		FindInBatches(&tags, 500, func(tx *gorm.DB, batch int) error {
+			for _, tag := range tags {
+				err := tx.
+					Model(&TagCatalogue{}).
+					Where("id = ?", tag.ID).
+					Update("slug", Slug(tag.Name)).
+					Error
+
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	if split {
+		err = splitByTeam(tx)
+		if err != nil {
+			return err
+		}
+
+		err = dedupeSlug(tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if tx.Migrator().HasIndex(&TagCatalogue{}, "order_tag_team_slug") {
+		return nil
+	}
+
+	return tx.Migrator().CreateIndex(&TagCatalogue{}, "order_tag_team_slug")
+}
@@ -0,0 +1,265 @@
+package tag_core
+
+import (
+	"github.com/pdcgo/shared/db_models"
+	"gorm.io/gorm"
+)
+
+// relation_from yang memakai tag sistem, termasuk nilai lama dari OrderTagAdd
+var systemFroms = []string{
+	string(db_models.RelationFromTracking),
+	string(db_models.RelationFromWarehouse),
+	"TAG_TYPE_TRACKING",
+	"TAG_TYPE_WAREHOUSE",
+}
+
+// moveRelations pindahkan relasi hasil scope ke tag lain, order yang sudah punya tag tujuan tidak diduplikasi.
+// relasi sumber dicatat remove dan relasi baru di tag tujuan dicatat add
+func moveRelations(tx *gorm.DB, actor *TagActor, targetID uint, scope func(tx *gorm.DB) *gorm.DB) (int64, error) {
+	relations := func(columns ...string) *gorm.DB {
+		return tx.
+			Session(&gorm.Session{NewDB: true}).
+			Model(&db_models.OrderTagRelation{}).
+			Scopes(scope).
+			Select(columns)
+	}
+
+	moving := []*db_models.OrderTagRelation{}
+	err := relations("order_id", "order_tag_id", "relation_from").
+		Find(&moving).
+		Error
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(moving) == 0 {
+		return 0, nil
+	}
+
+	orderIDs := []uint{}
+	for _, rel := range moving {
+		orderIDs = append(orderIDs, rel.OrderID)
+	}
+
+	existing := []uint{}
+	err = tx.
+		Model(&db_models.OrderTagRelation{}).
+		Where("order_tag_id = ?", targetID).
+		Where("order_id IN ?", orderIDs).
+		Pluck("order_id", &existing).
+		Error
+
+	if err != nil {
+		return 0, err
+	}
+
+	// relation_from relasi baru sama dengan yang dipilih insert (MIN per order)
+	hasTarget := map[uint]bool{}
+	for _, orderID := range existing {
+		hasTarget[orderID] = true
+	}
+
+	added := []*db_models.OrderTagRelation{}
+	addedBy := map[uint]*db_models.OrderTagRelation{}
+	for _, rel := range moving {
+		if hasTarget[rel.OrderID] {
+			continue
+		}
+
+		item, ok := addedBy[rel.OrderID]
+		if !ok {
+			item = &db_models.OrderTagRelation{
+				OrderID:      rel.OrderID,
+				OrderTagID:   targetID,
+				RelationFrom: rel.RelationFrom,
+			}
+			addedBy[rel.OrderID] = item
+			added = append(added, item)
+			continue
+		}
+
+		if rel.RelationFrom < item.RelationFrom {
+			item.RelationFrom = rel.RelationFrom
+		}
+	}
+
+	res := tx.Exec(`
+		INSERT INTO order_tag_relations (order_id, order_tag_id, relation_from)
+		SELECT order_id, ?, MIN(relation_from)
+		FROM (?) AS moved
+		GROUP BY order_id
+		ON CONFLICT DO NOTHING
+	`, targetID, relations("order_id", "relation_from"))
+
+	if res.Error != nil {
+		return 0, res.Error
+	}
+
+	// history dicatat sebelum tag sumber dihapus supaya nama tag masih terbaca
+	err = RecordHistory(tx, actor, TagRemoved, moving)
+	if err != nil {
+		return 0, err
+	}
+
+	err = RecordHistory(tx, actor, TagAdded, added)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.
+		Where("(order_id, order_tag_id) IN (?)", relations("order_id", "order_tag_id")).
+		Delete(&db_models.OrderTagRelation{}).
+		Error
+
+	return res.RowsAffected, err
+}
+
+type teamUsage struct {
+	OrderTagID uint
+	TeamID     uint
+}
+
+// splitByTeam tag lama yang dipakai user dari beberapa team dipecah jadi tag per team.
+// relasi tracking dan warehouse tetap di tag lama sebagai tag sistem
+func splitByTeam(tx *gorm.DB) error {
+	usages := []*teamUsage{}
+	err := tx.
+		Table("order_tag_relations r").
+		Select("r.order_tag_id", "o.team_id").
+		Joins("JOIN orders o ON o.id = r.order_id").
+		Joins("JOIN order_tags t ON t.id = r.order_tag_id").
+		Where("t.team_id = ?", SystemTeamID).
+		Where("o.team_id != ?", SystemTeamID).
+		Where("COALESCE(r.relation_from, '') NOT IN ?", systemFroms).
+		Group("r.order_tag_id, o.team_id").
+		Order("r.order_tag_id asc, o.team_id asc").
+		Find(&usages).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	splitted := map[uint]bool{}
+	for _, usage := range usages {
+		var tag TagCatalogue
+		err = tx.
+			Model(&TagCatalogue{}).
+			Where("id = ?", usage.OrderTagID).
+			Find(&tag).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		teamTag := TagCatalogue{
+			TeamID:      usage.TeamID,
+			Name:        tag.Name,
+			Slug:        tag.Slug,
+			Color:       tag.Color,
+			Description: tag.Description,
+			Archived:    tag.Archived,
+		}
+
+		err = tx.Create(&teamTag).Error
+		if err != nil {
+			return err
+		}
+
+		teamOrders := tx.
+			Session(&gorm.Session{NewDB: true}).
+			Model(&db_models.Order{}).
+			Select("id").
+			Where("team_id = ?", usage.TeamID)
+
+		tagID := tag.ID
+		relations := func(tx *gorm.DB) *gorm.DB {
+			return tx.
+				Where("order_tag_id = ?", tagID).
+				Where("COALESCE(relation_from, '') NOT IN ?", systemFroms).
+				Where("order_id IN (?)", teamOrders)
+		}
+
+		_, err = moveRelations(tx, SystemActor, teamTag.ID, relations)
+		if err != nil {
+			return err
+		}
+
+		splitted[tag.ID] = true
+	}
+
+	// tag lama yang semua relasinya sudah pindah ke team dihapus
+	for tagID := range splitted {
+		var count int64
+		err = tx.
+			Model(&db_models.OrderTagRelation{}).
+			Where("order_tag_id = ?", tagID).
+			Count(&count).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		if count != 0 {
+			continue
+		}
+
+		err = tx.
+			Where("id = ?", tagID).
+			Delete(&TagCatalogue{}).
+			Error
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type slugDuplicate struct {
+	TeamID uint
+	Slug   string
+}
+
+// dedupeSlug tag dengan slug sama di satu team digabung ke id terkecil
+// supaya index unik team_id, slug bisa dibuat
+func dedupeSlug(tx *gorm.DB) error {
+	duplicates := []*slugDuplicate{}
+	err := tx.
+		Model(&TagCatalogue{}).
+		Select("team_id", "slug").
+		Group("team_id, slug").
+		Having("COUNT(*) > 1").
+		Find(&duplicates).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	for _, dup := range duplicates {
+		ids := []uint{}
+		err = tx.
+			Model(&TagCatalogue{}).
+			Where("team_id = ?", dup.TeamID).
+			Where("slug = ?", dup.Slug).
+			Order("id asc").
+			Pluck("id", &ids).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		_, err = mergeInto(tx, SystemActor, dup.TeamID, ids[0], ids[1:])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package tag_core_test
+
+import (
+	"testing"
+
+	"github.com/pdcgo/order_service/order/tag_core"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/pkg/moretest"
+	"github.com/pdcgo/shared/pkg/moretest/moretest_mock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestSplitTagByTeam(t *testing.T) {
+	var db gorm.DB
+
+	var migration moretest.SetupFunc = func(t *testing.T) func() error {
+		err := db.AutoMigrate(
+			&db_models.Order{},
+			&db_models.OrderTag{},
+			&db_models.OrderTagRelation{},
+		)
+		assert.Nil(t, err)
+		return nil
+	}
+
+	var seed moretest.SetupFunc = func(t *testing.T) func() error {
+		orders := []*db_models.Order{
+			{ID: 1, TeamID: 3},
+			{ID: 2, TeamID: 4},
+			{ID: 3, TeamID: 3},
+		}
+		err := db.Save(&orders).Error
+		assert.Nil(t, err)
+
+		tags := []*db_models.OrderTag{
+			{ID: 1, Name: "retur"},
+			{ID: 2, Name: "selesai"},
+			{ID: 3, Name: "Retur "},
+		}
+		err = db.Save(&tags).Error
+		assert.Nil(t, err)
+
+		relations := []*db_models.OrderTagRelation{
+			{OrderID: 1, OrderTagID: 1, RelationFrom: "user"},
+			{OrderID: 2, OrderTagID: 1, RelationFrom: "user"},
+			{OrderID: 1, OrderTagID: 3, RelationFrom: "user"},
+			{OrderID: 3, OrderTagID: 2, RelationFrom: "tracking"},
+		}
+		err = db.Save(&relations).Error
+		assert.Nil(t, err)
+		return nil
+	}
+
+	moretest.Suite(t, "testing split tag per team",
+		moretest.SetupListFunc{
+			moretest_mock.MockSqliteDatabase(&db),
+			migration,
+			seed,
+		},
+		func(t *testing.T) {
+			err := tag_core.Migrate(&db)
+			assert.Nil(t, err)
+
+			t.Run("tag user pindah ke team", func(t *testing.T) {
+				for _, teamID := range []uint{3, 4} {
+					tags := []*tag_core.TagCatalogue{}
+					err := db.Where("team_id = ? AND slug = ?", teamID, "retur").Find(&tags).Error
+					assert.Nil(t, err)
+					assert.Len(t, tags, 1)
+				}
+
+				var count int64
+				err := db.Model(&tag_core.TagCatalogue{}).Where("id IN ?", []uint{1, 3}).Count(&count).Error
+				assert.Nil(t, err)
+				assert.Equal(t, int64(0), count)
+			})
+
+			t.Run("tag tracking tetap tag sistem", func(t *testing.T) {
+				tag, err := tag_core.Get(&db, 3, 2)
+				assert.Nil(t, err)
+				assert.Equal(t, tag_core.SystemTeamID, tag.TeamID)
+			})
+
+			t.Run("tag team lain tidak terlihat", func(t *testing.T) {
+				ids, err := tag_core.Lookup(&db, 4, []string{"retur"})
+				assert.Nil(t, err)
+				assert.Len(t, ids, 1)
+
+				var relation db_models.OrderTagRelation
+				err = db.Where("order_id = ?", 2).First(&relation).Error
+				assert.Nil(t, err)
+				assert.Equal(t, ids[0], relation.OrderTagID)
+
+				other, err := tag_core.Lookup(&db, 3, []string{"retur"})
+				assert.Nil(t, err)
+				assert.NotEqual(t, ids[0], other[0])
+			})
+
+			t.Run("nama sama di team berbeda", func(t *testing.T) {
+				_, err := tag_core.Create(&db, 5, &tag_core.TagData{Name: "retur"})
+				assert.Nil(t, err)
+			})
+		},
+	)
+}
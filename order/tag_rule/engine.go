@@ -0,0 +1,204 @@
+package tag_rule
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pdcgo/order_service/order/tag_core"
+	"github.com/pdcgo/order_service/order_mutation"
+	"github.com/pdcgo/shared/db_models"
+	"gorm.io/gorm"
+)
+
+// EvaluateResult jumlah order yang dievaluasi, cocok dan tidak cocok per tag
+type EvaluateResult struct {
+	Orders    int
+	Matched   int
+	Unmatched int
+}
+
+type Engine struct {
+	tx  *gorm.DB
+	now time.Time
+}
+
+func NewEngine(tx *gorm.DB, now time.Time) *Engine {
+	return &Engine{
+		tx:  tx,
+		now: now,
+	}
+}
+
+func (e *Engine) activeRules(teamIDs []uint) (map[uint][]*TagRule, error) {
+	rules := []*TagRule{}
+	err := e.tx.
+		Model(&TagRule{}).
+		Where("team_id IN ?", teamIDs).
+		Where("active = ?", true).
+		Order("id asc").
+		Find(&rules).
+		Error
+
+	byTeam := map[uint][]*TagRule{}
+	for _, rule := range rules {
+		byTeam[rule.TeamID] = append(byTeam[rule.TeamID], rule)
+	}
+
+	return byTeam, err
+}
+
+// Evaluate pasang tag rule ke order yang cocok dan lepas dari order yang tidak cocok.
+// beberapa rule dengan tag sama dianggap cocok jika salah satu cocok
+func (e *Engine) Evaluate(orderIDs []uint) (*EvaluateResult, error) {
+	result := EvaluateResult{}
+	if len(orderIDs) == 0 {
+		return &result, nil
+	}
+
+	facts, err := loadFacts(e.tx, orderIDs, e.now)
+	if err != nil {
+		return &result, err
+	}
+
+	teamIDs := []uint{}
+	byTeam := map[uint][]*OrderFacts{}
+	for _, fact := range facts {
+		if _, ok := byTeam[fact.TeamID]; !ok {
+			teamIDs = append(teamIDs, fact.TeamID)
+		}
+		byTeam[fact.TeamID] = append(byTeam[fact.TeamID], fact)
+	}
+
+	rules, err := e.activeRules(teamIDs)
+	if err != nil {
+		return &result, err
+	}
+
+	tagmut := order_mutation.NewTagMutation(e.tx)
+	for _, teamID := range teamIDs {
+		if len(rules[teamID]) == 0 {
+			continue
+		}
+
+		tags := map[string]string{}
+		matched := map[string]map[uint]bool{}
+		for _, rule := range rules[teamID] {
+			slug := tag_core.Slug(rule.Tag)
+			if _, ok := tags[slug]; !ok {
+				tags[slug] = rule.Tag
+				matched[slug] = map[uint]bool{}
+			}
+
+			for _, fact := range byTeam[teamID] {
+				if rule.Match(fact) {
+					matched[slug][fact.OrderID] = true
+				}
+			}
+		}
+
+		slugs := make([]string, 0, len(tags))
+		for slug := range tags {
+			slugs = append(slugs, slug)
+		}
+		sort.Strings(slugs)
+
+		for _, slug := range slugs {
+			add := []uint{}
+			remove := []uint{}
+			for _, fact := range byTeam[teamID] {
+				if matched[slug][fact.OrderID] {
+					add = append(add, fact.OrderID)
+				} else {
+					remove = append(remove, fact.OrderID)
+				}
+			}
+
+			if len(add) != 0 {
+				err = tagmut.Add(RelationFromRule, add, []string{tags[slug]})
+				if err != nil {
+					return &result, err
+				}
+			}
+
+			if len(remove) != 0 {
+				err = tagmut.Remove(RelationFromRule, remove, []string{tags[slug]})
+				if err != nil {
+					return &result, err
+				}
+			}
+
+			result.Matched += len(add)
+			result.Unmatched += len(remove)
+		}
+
+		result.Orders += len(byTeam[teamID])
+	}
+
+	return &result, nil
+}
+
+// EvaluateAll evaluasi semua order team yang punya rule aktif per batch, teamID 0 berarti semua team
+func EvaluateAll(ctx context.Context, db *gorm.DB, teamID uint, now time.Time) (*EvaluateResult, error) {
+	result := EvaluateResult{}
+
+	teamIDs := []uint{}
+	query := db.
+		WithContext(ctx).
+		Model(&TagRule{}).
+		Distinct("team_id").
+		Where("active = ?", true)
+
+	if teamID != 0 {
+		query = query.Where("team_id = ?", teamID)
+	}
+
+	err := query.Pluck("team_id", &teamIDs).Error
+	if err != nil {
+		return &result, err
+	}
+
+	if len(teamIDs) == 0 {
+		return &result, nil
+	}
+
+	var lastID uint
+	for {
+		orderIDs := []uint{}
+		err = db.
+			WithContext(ctx).
+			Model(&db_models.Order{}).
+			Where("team_id IN ?", teamIDs).
+			Where("id > ?", lastID).
+			Order("id asc").
+			Limit(500).
+			Pluck("id", &orderIDs).
+			Error
+
+		if err != nil {
+			return &result, err
+		}
+
+		if len(orderIDs) == 0 {
+			return &result, nil
+		}
+
+		lastID = orderIDs[len(orderIDs)-1]
+
+		err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			batch, err := NewEngine(tx, now).Evaluate(orderIDs)
+			if err != nil {
+				return err
+			}
+
+			result.Orders += batch.Orders
+			result.Matched += batch.Matched
+			result.Unmatched += batch.Unmatched
+			return nil
+		})
+
+		if err != nil {
+			return &result, err
+		}
+	}
+}
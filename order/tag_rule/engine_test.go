@@ -0,0 +1,151 @@
+package tag_rule_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pdcgo/order_service/order/tag_core"
+	"github.com/pdcgo/order_service/order/tag_rule"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/pkg/moretest"
+	"github.com/pdcgo/shared/pkg/moretest/moretest_mock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestTagRuleEngine(t *testing.T) {
+	var db gorm.DB
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	var migration moretest.SetupFunc = func(t *testing.T) func() error {
+		err := db.AutoMigrate(
+			&db_models.Order{},
+			&db_models.OrderTimestamp{},
+			&db_models.OrderAdjustment{},
+			&db_models.OrderTag{},
+			&db_models.OrderTagRelation{},
+			&tag_core.OrderTagHistory{},
+			&tag_rule.TagRule{},
+		)
+		assert.Nil(t, err)
+
+		err = tag_core.Migrate(&db)
+		assert.Nil(t, err)
+		return nil
+	}
+
+	var seed moretest.SetupFunc = func(t *testing.T) func() error {
+		orders := []*db_models.Order{
+			{ID: 1, TeamID: 3, Status: db_models.OrdCompleted, Total: 2_000_000, CreatedAt: now.Add(-100 * time.Hour)},
+			{ID: 2, TeamID: 3, Status: db_models.OrdCompleted, Total: 50_000, CreatedAt: now.Add(-100 * time.Hour)},
+			{ID: 3, TeamID: 4, Status: db_models.OrdCompleted, Total: 3_000_000, CreatedAt: now.Add(-100 * time.Hour)},
+		}
+		err := db.Save(&orders).Error
+		assert.Nil(t, err)
+
+		timestamps := []*db_models.OrderTimestamp{
+			{OrderID: 2, OrderStatus: db_models.OrdCompleted, Timestamp: now.Add(-2 * time.Hour)},
+		}
+		err = db.Save(&timestamps).Error
+		assert.Nil(t, err)
+
+		adjustments := []*db_models.OrderAdjustment{
+			{OrderID: 2, Amount: -20_000},
+			{OrderID: 2, Amount: -5_000},
+		}
+		err = db.Save(&adjustments).Error
+		assert.Nil(t, err)
+		return nil
+	}
+
+	moretest.Suite(t, "testing tag rule",
+		moretest.SetupListFunc{
+			moretest_mock.MockSqliteDatabase(&db),
+			migration,
+			seed,
+		},
+		func(t *testing.T) {
+			highValue := tag_rule.TagRule{
+				TeamID: 3,
+				Name:   "order besar",
+				Tag:    "high_value",
+				Active: true,
+				Conditions: []*tag_rule.RuleCondition{
+					{Field: tag_rule.FieldTotal, Op: tag_rule.OpGte, Value: "1000000"},
+				},
+			}
+
+			t.Run("rule tidak valid", func(t *testing.T) {
+				rule := tag_rule.TagRule{
+					TeamID: 3,
+					Tag:    "x",
+					Conditions: []*tag_rule.RuleCondition{
+						{Field: tag_rule.FieldTotal, Op: tag_rule.OpIn, Value: "1"},
+					},
+				}
+				err := tag_rule.Save(&db, &rule, now)
+				assert.True(t, errors.Is(err, order_errors.ErrRuleInvalid))
+			})
+
+			t.Run("evaluasi rule", func(t *testing.T) {
+				err := tag_rule.Save(&db, &highValue, now)
+				assert.Nil(t, err)
+
+				late := tag_rule.TagRule{
+					TeamID: 3,
+					Tag:    "adjusted_recent",
+					Active: true,
+					Conditions: []*tag_rule.RuleCondition{
+						{Field: tag_rule.FieldAgeHours, Op: tag_rule.OpLt, Value: "24"},
+						{Field: tag_rule.FieldAdjustmentTotal, Op: tag_rule.OpLt, Value: "-10000"},
+						{Field: tag_rule.FieldStatus, Op: tag_rule.OpIn, Value: "completed,return"},
+					},
+				}
+				err = tag_rule.Save(&db, &late, now)
+				assert.Nil(t, err)
+
+				result, err := tag_rule.EvaluateAll(t.Context(), &db, 0, now)
+				assert.Nil(t, err)
+				assert.Equal(t, 2, result.Orders)
+
+				relations := []*db_models.OrderTagRelation{}
+				err = db.Order("order_id asc").Find(&relations).Error
+				assert.Nil(t, err)
+				assert.Len(t, relations, 2)
+				assert.Equal(t, uint(1), relations[0].OrderID)
+				assert.Equal(t, uint(2), relations[1].OrderID)
+				assert.Equal(t, string(tag_rule.RelationFromRule), relations[0].RelationFrom)
+			})
+
+			t.Run("order tidak cocok lagi tag dilepas", func(t *testing.T) {
+				err := db.Model(&db_models.Order{}).Where("id = ?", 1).Update("total", 10_000).Error
+				assert.Nil(t, err)
+
+				_, err = tag_rule.NewEngine(&db, now).Evaluate([]uint{1})
+				assert.Nil(t, err)
+
+				var count int64
+				err = db.Model(&db_models.OrderTagRelation{}).Where("order_id = ?", 1).Count(&count).Error
+				assert.Nil(t, err)
+				assert.Equal(t, int64(0), count)
+			})
+
+			t.Run("hapus rule lepas tag", func(t *testing.T) {
+				rules, err := tag_rule.List(&db, 3)
+				assert.Nil(t, err)
+
+				for _, rule := range rules {
+					err = tag_rule.Delete(&db, 3, rule.ID)
+					assert.Nil(t, err)
+				}
+
+				var count int64
+				err = db.Model(&db_models.OrderTagRelation{}).Count(&count).Error
+				assert.Nil(t, err)
+				assert.Equal(t, int64(0), count)
+			})
+		},
+	)
+}
@@ -0,0 +1,255 @@
+package tag_rule
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pdcgo/order_service/order/tag_core"
+	"github.com/pdcgo/shared/db_models"
+	"gorm.io/gorm"
+)
+
+// OrderFacts data order yang dibaca kondisi rule
+type OrderFacts struct {
+	OrderID         uint
+	TeamID          uint
+	Status          string
+	OrderFrom       string
+	Total           float64
+	MpTotal         float64
+	AgeHours        float64
+	AdjustmentTotal float64
+	TrackingTags    map[string]bool
+}
+
+func loadFacts(tx *gorm.DB, orderIDs []uint, now time.Time) ([]*OrderFacts, error) {
+	facts := []*OrderFacts{}
+
+	orders := []*db_models.Order{}
+	err := tx.
+		Model(&db_models.Order{}).
+		Select("id", "team_id", "status", "order_from", "total", "order_mp_total", "created_at").
+		Where("id IN ?", orderIDs).
+		Order("id asc").
+		Find(&orders).
+		Error
+
+	if err != nil {
+		return facts, err
+	}
+
+	if len(orders) == 0 {
+		return facts, nil
+	}
+
+	// umur dihitung dari perubahan status terakhir
+	lastStatus := []*struct {
+		OrderID uint
+		Last    string
+	}{}
+	err = tx.
+		Model(&db_models.OrderTimestamp{}).
+		Select("order_id", "MAX(timestamp) AS last").
+		Where("order_id IN ?", orderIDs).
+		Group("order_id").
+		Find(&lastStatus).
+		Error
+
+	if err != nil {
+		return facts, err
+	}
+
+	adjustments := []*struct {
+		OrderID uint
+		Total   float64
+	}{}
+	err = tx.
+		Model(&db_models.OrderAdjustment{}).
+		Select("order_id", "SUM(amount) AS total").
+		Where("order_id IN ?", orderIDs).
+		Where("deleted = ?", false).
+		Group("order_id").
+		Find(&adjustments).
+		Error
+
+	if err != nil {
+		return facts, err
+	}
+
+	tracking := []*struct {
+		OrderID uint
+		Slug    string
+	}{}
+	err = tx.
+		Table("order_tag_relations r").
+		Select("r.order_id", "t.slug").
+		Joins("JOIN order_tags t ON t.id = r.order_tag_id").
+		Where("r.order_id IN ?", orderIDs).
+		Where("r.relation_from = ?", db_models.RelationFromTracking).
+		Find(&tracking).
+		Error
+
+	if err != nil {
+		return facts, err
+	}
+
+	byID := map[uint]*OrderFacts{}
+	for _, ord := range orders {
+		fact := &OrderFacts{
+			OrderID:      ord.ID,
+			TeamID:       ord.TeamID,
+			Status:       string(ord.Status),
+			OrderFrom:    string(ord.OrderFrom),
+			Total:        ord.Total,
+			MpTotal:      float64(ord.OrderMpTotal),
+			AgeHours:     now.Sub(ord.CreatedAt).Hours(),
+			TrackingTags: map[string]bool{},
+		}
+
+		byID[ord.ID] = fact
+		facts = append(facts, fact)
+	}
+
+	for _, item := range lastStatus {
+		last, err := parseDBTime(item.Last)
+		if err != nil {
+			return facts, err
+		}
+
+		if fact := byID[item.OrderID]; fact != nil && !last.IsZero() {
+			fact.AgeHours = now.Sub(last).Hours()
+		}
+	}
+
+	for _, item := range adjustments {
+		if fact := byID[item.OrderID]; fact != nil {
+			fact.AdjustmentTotal = item.Total
+		}
+	}
+
+	for _, item := range tracking {
+		if fact := byID[item.OrderID]; fact != nil {
+			fact.TrackingTags[item.Slug] = true
+		}
+	}
+
+	return facts, nil
+}
+
+// parseDBTime hasil MAX(timestamp) dibaca string, format berbeda antara postgres dan sqlite
+func parseDBTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	layouts := []string{
+		time.RFC3339Nano,
+		"2006-01-02 15:04:05.999999999-07:00",
+		"2006-01-02 15:04:05.999999999Z07:00",
+		"2006-01-02 15:04:05.999999999",
+	}
+
+	var err error
+	for _, layout := range layouts {
+		var t time.Time
+		t, err = time.Parse(layout, value)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, err
+}
+
+func (f *OrderFacts) text(field RuleField) []string {
+	switch field {
+	case FieldStatus:
+		return []string{f.Status}
+	case FieldOrderFrom:
+		return []string{f.OrderFrom}
+	case FieldTrackingStatus:
+		tags := []string{}
+		for slug := range f.TrackingTags {
+			tags = append(tags, slug)
+		}
+		return tags
+	}
+	return []string{}
+}
+
+func (f *OrderFacts) number(field RuleField) float64 {
+	switch field {
+	case FieldTotal:
+		return f.Total
+	case FieldMpTotal:
+		return f.MpTotal
+	case FieldAgeHours:
+		return f.AgeHours
+	case FieldAdjustmentTotal:
+		return f.AdjustmentTotal
+	}
+	return 0
+}
+
+func normalizeText(field RuleField, value string) string {
+	if field == FieldTrackingStatus {
+		return tag_core.Slug(value)
+	}
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// Match kondisi terhadap order, kondisi dianggap sudah divalidasi
+func (c *RuleCondition) Match(f *OrderFacts) bool {
+	if numericFields[c.Field] {
+		value, _ := strconv.ParseFloat(c.Value, 64)
+		actual := f.number(c.Field)
+
+		switch c.Op {
+		case OpEq:
+			return actual == value
+		case OpNeq:
+			return actual != value
+		case OpGt:
+			return actual > value
+		case OpGte:
+			return actual >= value
+		case OpLt:
+			return actual < value
+		case OpLte:
+			return actual <= value
+		}
+		return false
+	}
+
+	expected := map[string]bool{}
+	values := []string{c.Value}
+	if c.Op == OpIn {
+		values = strings.Split(c.Value, ",")
+	}
+	for _, value := range values {
+		expected[normalizeText(c.Field, value)] = true
+	}
+
+	found := false
+	for _, actual := range f.text(c.Field) {
+		if expected[normalizeText(c.Field, actual)] {
+			found = true
+			break
+		}
+	}
+
+	if c.Op == OpNeq {
+		return !found
+	}
+	return found
+}
+
+func (r *TagRule) Match(f *OrderFacts) bool {
+	for _, cond := range r.Conditions {
+		if !cond.Match(f) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,138 @@
+package tag_rule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pdcgo/order_service/order/tag_core"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/shared/db_models"
+	"gorm.io/datatypes"
+)
+
+// RelationFromRule relasi tag yang dipasang rule, tidak bentrok dengan tag manual atau tracking
+const RelationFromRule db_models.RelationFrom = "rule"
+
+type RuleField string
+
+const (
+	FieldStatus    RuleField = "status"
+	FieldOrderFrom RuleField = "order_from"
+	FieldTotal     RuleField = "total"
+	FieldMpTotal   RuleField = "mp_total"
+	// jam sejak OrderTimestamp terakhir
+	FieldAgeHours RuleField = "age_hours"
+	// jumlah adjustment yang belum dihapus
+	FieldAdjustmentTotal RuleField = "adjustment_total"
+	// tag dari tracking, dicocokkan lewat slug
+	FieldTrackingStatus RuleField = "tracking_status"
+)
+
+type RuleOp string
+
+const (
+	OpEq  RuleOp = "eq"
+	OpNeq RuleOp = "neq"
+	OpGt  RuleOp = "gt"
+	OpGte RuleOp = "gte"
+	OpLt  RuleOp = "lt"
+	OpLte RuleOp = "lte"
+	// value dipisah koma
+	OpIn RuleOp = "in"
+)
+
+var numericFields = map[RuleField]bool{
+	FieldTotal:           true,
+	FieldMpTotal:         true,
+	FieldAgeHours:        true,
+	FieldAdjustmentTotal: true,
+}
+
+var textFields = map[RuleField]bool{
+	FieldStatus:         true,
+	FieldOrderFrom:      true,
+	FieldTrackingStatus: true,
+}
+
+func ruleErr(format string, args ...any) error {
+	return order_errors.RuleInvalid(fmt.Sprintf(format, args...))
+}
+
+type RuleCondition struct {
+	Field RuleField `json:"field"`
+	Op    RuleOp    `json:"op"`
+	Value string    `json:"value"`
+}
+
+func (c *RuleCondition) validate() error {
+	switch {
+	case numericFields[c.Field]:
+		switch c.Op {
+		case OpEq, OpNeq, OpGt, OpGte, OpLt, OpLte:
+		default:
+			return ruleErr("operator %s tidak bisa dipakai untuk %s", c.Op, c.Field)
+		}
+
+		_, err := strconv.ParseFloat(c.Value, 64)
+		if err != nil {
+			return ruleErr("nilai %s untuk %s harus angka", c.Value, c.Field)
+		}
+	case textFields[c.Field]:
+		switch c.Op {
+		case OpEq, OpNeq, OpIn:
+		default:
+			return ruleErr("operator %s tidak bisa dipakai untuk %s", c.Op, c.Field)
+		}
+
+		if strings.TrimSpace(c.Value) == "" {
+			return ruleErr("nilai %s kosong", c.Field)
+		}
+	default:
+		return ruleErr("field %s tidak dikenal", c.Field)
+	}
+
+	return nil
+}
+
+// TagRule rule tag otomatis per team, semua kondisi harus terpenuhi
+type TagRule struct {
+	ID         uint                                `json:"id" gorm:"primarykey"`
+	TeamID     uint                                `json:"team_id" gorm:"index"`
+	Name       string                              `json:"name"`
+	Tag        string                              `json:"tag"`
+	Conditions datatypes.JSONSlice[*RuleCondition] `json:"conditions"`
+	Active     bool                                `json:"active" gorm:"index"`
+	CreatedBy  uint                                `json:"created_by"`
+	Created    time.Time                           `json:"created"`
+	Updated    time.Time                           `json:"updated"`
+}
+
+func (r *TagRule) GetEntityID() string {
+	return "order_tag_rule"
+}
+
+func (r *TagRule) Validate() error {
+	r.Tag = tag_core.NormalizeName(r.Tag)
+	if tag_core.Slug(r.Tag) == "" {
+		return ruleErr("tag kosong")
+	}
+
+	if len(r.Tag) > tag_core.MaxNameLength {
+		return ruleErr("tag lebih dari %d karakter", tag_core.MaxNameLength)
+	}
+
+	if len(r.Conditions) == 0 {
+		return ruleErr("kondisi kosong")
+	}
+
+	for _, cond := range r.Conditions {
+		err := cond.validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
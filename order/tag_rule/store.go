@@ -0,0 +1,127 @@
+package tag_rule
+
+import (
+	"time"
+
+	"github.com/pdcgo/order_service/order/tag_core"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/shared/db_models"
+	"gorm.io/gorm"
+)
+
+func List(tx *gorm.DB, teamID uint) ([]*TagRule, error) {
+	rules := []*TagRule{}
+	err := tx.
+		Model(&TagRule{}).
+		Where("team_id = ?", teamID).
+		Order("id asc").
+		Find(&rules).
+		Error
+
+	return rules, err
+}
+
+func get(tx *gorm.DB, teamID uint, ruleID uint) (*TagRule, error) {
+	var rule TagRule
+	err := tx.
+		Model(&TagRule{}).
+		Where("id = ?", ruleID).
+		Where("team_id = ?", teamID).
+		Find(&rule).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	if rule.ID == 0 {
+		return nil, order_errors.RuleNotFound(uint64(ruleID))
+	}
+
+	return &rule, nil
+}
+
+// Save buat atau ubah rule, tag lama yang sudah tidak dipakai rule aktif dilepas dari order
+func Save(tx *gorm.DB, rule *TagRule, now time.Time) error {
+	err := rule.Validate()
+	if err != nil {
+		return err
+	}
+
+	rule.Updated = now
+	if rule.ID == 0 {
+		rule.Created = now
+		return tx.Create(rule).Error
+	}
+
+	old, err := get(tx, rule.TeamID, rule.ID)
+	if err != nil {
+		return err
+	}
+
+	rule.Created = old.Created
+	rule.CreatedBy = old.CreatedBy
+	err = tx.Save(rule).Error
+	if err != nil {
+		return err
+	}
+
+	return releaseTag(tx, old.TeamID, old.Tag)
+}
+
+func Delete(tx *gorm.DB, teamID uint, ruleID uint) error {
+	rule, err := get(tx, teamID, ruleID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.
+		Where("id = ?", rule.ID).
+		Delete(&TagRule{}).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	return releaseTag(tx, rule.TeamID, rule.Tag)
+}
+
+// releaseTag hapus relasi rule untuk tag yang tidak lagi dipakai rule aktif
+func releaseTag(tx *gorm.DB, teamID uint, tag string) error {
+	rules := []*TagRule{}
+	err := tx.
+		Model(&TagRule{}).
+		Where("team_id = ?", teamID).
+		Where("active = ?", true).
+		Find(&rules).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	slug := tag_core.Slug(tag)
+	for _, rule := range rules {
+		if tag_core.Slug(rule.Tag) == slug {
+			return nil
+		}
+	}
+
+	tagIDs, err := tag_core.Lookup(tx, teamID, []string{tag})
+	if err != nil || len(tagIDs) == 0 {
+		return err
+	}
+
+	return tag_core.RemoveRelations(tx, tag_core.SystemActor, func(q *gorm.DB) *gorm.DB {
+		return q.
+			Where("relation_from = ?", RelationFromRule).
+			Where("order_tag_id IN ?", tagIDs).
+			Where("order_id IN (?)", tx.
+				Session(&gorm.Session{NewDB: true}).
+				Model(&db_models.Order{}).
+				Select("id").
+				Where("team_id = ?", teamID),
+			)
+	})
+}
@@ -0,0 +1,459 @@
+package order_errors
+
+import (
+	"fmt"
+
+	"connectrpc.com/connect"
+	"golang.org/x/text/language"
+)
+
+var (
+	Indonesian = language.Indonesian
+	English    = language.English
+)
+
+// bahasa pertama jadi default kalau Accept-Language tidak cocok
+var matcher = language.NewMatcher([]language.Tag{
+	Indonesian,
+	English,
+})
+
+type messageFunc func(meta map[string]string) string
+
+type catalogueEntry struct {
+	code     connect.Code
+	messages map[language.Tag]messageFunc
+}
+
+func orderLabel(meta map[string]string) string {
+	if ref := meta["order_ref_id"]; ref != "" {
+		return ref
+	}
+	if id := meta["order_id"]; id != "" {
+		return "#" + id
+	}
+	return ""
+}
+
+var catalogue = map[Reason]catalogueEntry{
+	ReasonOrderNotFound: {
+		code: connect.CodeNotFound,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("order %s tidak ditemukan", orderLabel(meta))
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("order %s not found", orderLabel(meta))
+			},
+		},
+	},
+	ReasonWrongTeam: {
+		code: connect.CodePermissionDenied,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("order %s bukan milik team %s", orderLabel(meta), meta["team_id"])
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("order %s does not belong to team %s", orderLabel(meta), meta["team_id"])
+			},
+		},
+	},
+	ReasonIllegalStatus: {
+		code: connect.CodeFailedPrecondition,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("status order %s %s, seharusnya %s", orderLabel(meta), meta["status"], meta["want"])
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("order %s status is %s, expected %s", orderLabel(meta), meta["status"], meta["want"])
+			},
+		},
+	},
+	ReasonDuplicateRefID: {
+		code: connect.CodeAlreadyExists,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("order dengan ref %s sudah ada", meta["order_ref_id"])
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("order with ref %s already exists", meta["order_ref_id"])
+			},
+		},
+	},
+	ReasonLockConflict: {
+		code: connect.CodeAborted,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("order %s sedang diproses transaksi lain, coba lagi", orderLabel(meta))
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("order %s is being processed by another transaction, try again", orderLabel(meta))
+			},
+		},
+	},
+	ReasonTagTooLong: {
+		code: connect.CodeInvalidArgument,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("tag kepanjangan, maksimal %s karakter", meta["max"])
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("tag is too long, maximum %s characters", meta["max"])
+			},
+		},
+	},
+	ReasonTagNotFound: {
+		code: connect.CodeNotFound,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("tag #%s tidak ditemukan", meta["tag_id"])
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("tag #%s not found", meta["tag_id"])
+			},
+		},
+	},
+	ReasonTagDuplicate: {
+		code: connect.CodeAlreadyExists,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("tag %s sudah ada", meta["tag"])
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("tag %s already exists", meta["tag"])
+			},
+		},
+	},
+	ReasonTagTypeDenied: {
+		code: connect.CodePermissionDenied,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("tag %s dengan tipe %s hanya bisa ditambahkan oleh sistem", meta["tag"], meta["tag_type"])
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("tag %s of type %s can only be added by the system", meta["tag"], meta["tag_type"])
+			},
+		},
+	},
+	ReasonEditExpired: {
+		code: connect.CodeFailedPrecondition,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("order %s sudah lebih dari %s hari, tidak bisa diubah", orderLabel(meta), meta["days"])
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("order %s is older than %s days and can no longer be changed", orderLabel(meta), meta["days"])
+			},
+		},
+	},
+	ReasonCurrencyMismatch: {
+		code: connect.CodeInvalidArgument,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("currency order %s %s, bukan %s", orderLabel(meta), meta["currency"], meta["want"])
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("order %s currency is %s, not %s", orderLabel(meta), meta["currency"], meta["want"])
+			},
+		},
+	},
+	ReasonReturnNotFound: {
+		code: connect.CodeNotFound,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("tidak ada order retur untuk transaksi #%s", meta["tx_id"])
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("no returned order for transaction #%s", meta["tx_id"])
+			},
+		},
+	},
+	ReasonZeroAmount: {
+		code: connect.CodeInvalidArgument,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("nominal payment order %s tidak boleh nol", orderLabel(meta))
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("payment amount for order %s must not be zero", orderLabel(meta))
+			},
+		},
+	},
+	ReasonMissingIdentifier: {
+		code: connect.CodeInvalidArgument,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return "order id atau ref id harus diisi"
+			},
+			English: func(meta map[string]string) string {
+				return "order id or ref id is required"
+			},
+		},
+	},
+	ReasonMarketplaceNotSet: {
+		code: connect.CodeFailedPrecondition,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("order %s belum punya marketplace", orderLabel(meta))
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("order %s has no marketplace set", orderLabel(meta))
+			},
+		},
+	},
+	ReasonFilterNotSupported: {
+		code: connect.CodeInvalidArgument,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return "filter tidak didukung"
+			},
+			English: func(meta map[string]string) string {
+				return "filter not supported"
+			},
+		},
+	},
+	ReasonDraftStale: {
+		code: connect.CodeAborted,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("draft #%s sudah diubah oleh user lain, muat ulang draft", meta["draft_id"])
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("draft #%s was changed by another user, reload the draft", meta["draft_id"])
+			},
+		},
+	},
+	ReasonDraftNotOwned: {
+		code: connect.CodePermissionDenied,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("draft #%s bukan milik user", meta["draft_id"])
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("draft #%s does not belong to the user", meta["draft_id"])
+			},
+		},
+	},
+	ReasonDraftDuplicateRef: {
+		code: connect.CodeAlreadyExists,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("order ref id %s sudah dipakai draft atau order lain", meta["order_ref_id"])
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("order ref id %s is already used by another draft or order", meta["order_ref_id"])
+			},
+		},
+	},
+	ReasonDraftUpgrade: {
+		code: connect.CodeFailedPrecondition,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("draft lama tidak bisa diupgrade: %s", meta["detail"])
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("legacy draft cannot be upgraded: %s", meta["detail"])
+			},
+		},
+	},
+	ReasonDraftTTLInvalid: {
+		code: connect.CodeInvalidArgument,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return "ttl draft harus lebih dari nol hari"
+			},
+			English: func(meta map[string]string) string {
+				return "draft ttl days must be greater than zero"
+			},
+		},
+	},
+	ReasonAssigneeNotInTeam: {
+		code: connect.CodeInvalidArgument,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("user #%s bukan anggota team %s", meta["user_id"], meta["team_id"])
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("user #%s is not a member of team %s", meta["user_id"], meta["team_id"])
+			},
+		},
+	},
+	ReasonShopNotInTeam: {
+		code: connect.CodeInvalidArgument,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("marketplace #%s bukan milik team %s", meta["shop_id"], meta["team_id"])
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("marketplace #%s does not belong to team %s", meta["shop_id"], meta["team_id"])
+			},
+		},
+	},
+	ReasonImportInvalid: {
+		code: connect.CodeInvalidArgument,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("file import tidak valid: %s", meta["detail"])
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("import file is invalid: %s", meta["detail"])
+			},
+		},
+	},
+	ReasonImportTooLarge: {
+		code: connect.CodeInvalidArgument,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("file lebih dari %s MB", meta["max"])
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("file is larger than %s MB", meta["max"])
+			},
+		},
+	},
+	ReasonInvalidTimezone: {
+		code: connect.CodeInvalidArgument,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("timezone %s tidak valid", meta["timezone"])
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("timezone %s is invalid", meta["timezone"])
+			},
+		},
+	},
+	ReasonTagEmptyName: {
+		code: connect.CodeInvalidArgument,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return "nama tag kosong"
+			},
+			English: func(meta map[string]string) string {
+				return "tag name is empty"
+			},
+		},
+	},
+	ReasonTagInvalidColor: {
+		code: connect.CodeInvalidArgument,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return "warna tag harus format #rrggbb"
+			},
+			English: func(meta map[string]string) string {
+				return "tag color must use the #rrggbb format"
+			},
+		},
+	},
+	ReasonTagMergeSelf: {
+		code: connect.CodeInvalidArgument,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return "tag tidak bisa digabung ke dirinya sendiri"
+			},
+			English: func(meta map[string]string) string {
+				return "a tag cannot be merged into itself"
+			},
+		},
+	},
+	ReasonViewNotFound: {
+		code: connect.CodeNotFound,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return "view tidak ditemukan"
+			},
+			English: func(meta map[string]string) string {
+				return "view not found"
+			},
+		},
+	},
+	ReasonViewEmptyName: {
+		code: connect.CodeInvalidArgument,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return "nama view kosong"
+			},
+			English: func(meta map[string]string) string {
+				return "view name is empty"
+			},
+		},
+	},
+	ReasonRuleNotFound: {
+		code: connect.CodeNotFound,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return "rule tag tidak ditemukan"
+			},
+			English: func(meta map[string]string) string {
+				return "tag rule not found"
+			},
+		},
+	},
+	ReasonRuleInvalid: {
+		code: connect.CodeInvalidArgument,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return fmt.Sprintf("rule tag tidak valid: %s", meta["detail"])
+			},
+			English: func(meta map[string]string) string {
+				return fmt.Sprintf("tag rule is invalid: %s", meta["detail"])
+			},
+		},
+	},
+	ReasonRecordNotFound: {
+		code: connect.CodeNotFound,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return "data tidak ditemukan"
+			},
+			English: func(meta map[string]string) string {
+				return "record not found"
+			},
+		},
+	},
+	ReasonDatabase: {
+		code: connect.CodeInternal,
+		messages: map[language.Tag]messageFunc{
+			Indonesian: func(meta map[string]string) string {
+				return "terjadi kesalahan database"
+			},
+			English: func(meta map[string]string) string {
+				return "database error"
+			},
+		},
+	},
+}
+
+// Language bahasa yang dipakai dari header Accept-Language
+func Language(acceptLanguage string) language.Tag {
+	tags, _, _ := language.ParseAcceptLanguage(acceptLanguage)
+	_, index, _ := matcher.Match(tags...)
+	switch index {
+	case 1:
+		return English
+	default:
+		return Indonesian
+	}
+}
+
+func message(reason Reason, lang language.Tag, meta map[string]string) string {
+	entry, ok := catalogue[reason]
+	if !ok {
+		return string(reason)
+	}
+
+	msg, ok := entry.messages[lang]
+	if !ok {
+		msg = entry.messages[English]
+	}
+
+	return msg(meta)
+}
+
+// Message pesan error untuk user, error di luar katalog dikembalikan apa adanya
+func Message(err error, lang language.Tag) string {
+	oerr, ok := As(err)
+	if !ok {
+		return err.Error()
+	}
+	return message(oerr.Reason, lang, oerr.Metadata)
+}
@@ -0,0 +1,375 @@
+package order_errors
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"connectrpc.com/connect"
+)
+
+// Domain dipakai di ErrorInfo supaya frontend bisa membedakan sumber error
+const Domain = "order.pdcgo"
+
+type Reason string
+
+const (
+	ReasonOrderNotFound  Reason = "ORDER_NOT_FOUND"
+	ReasonWrongTeam      Reason = "ORDER_WRONG_TEAM"
+	ReasonIllegalStatus  Reason = "ORDER_ILLEGAL_STATUS"
+	ReasonDuplicateRefID Reason = "ORDER_DUPLICATE_REF_ID"
+	ReasonLockConflict   Reason = "ORDER_LOCK_CONFLICT"
+	ReasonTagTooLong     Reason = "ORDER_TAG_TOO_LONG"
+	ReasonTagNotFound    Reason = "ORDER_TAG_NOT_FOUND"
+	ReasonTagDuplicate   Reason = "ORDER_TAG_DUPLICATE"
+	ReasonTagTypeDenied  Reason = "ORDER_TAG_TYPE_DENIED"
+	ReasonRecordNotFound Reason = "ORDER_RECORD_NOT_FOUND"
+	ReasonDatabase       Reason = "ORDER_DATABASE_ERROR"
+
+	ReasonEditExpired        Reason = "ORDER_EDIT_EXPIRED"
+	ReasonCurrencyMismatch   Reason = "ORDER_CURRENCY_MISMATCH"
+	ReasonReturnNotFound     Reason = "ORDER_RETURN_NOT_FOUND"
+	ReasonZeroAmount         Reason = "ORDER_ZERO_AMOUNT"
+	ReasonMissingIdentifier  Reason = "ORDER_MISSING_IDENTIFIER"
+	ReasonMarketplaceNotSet  Reason = "ORDER_MARKETPLACE_NOT_SET"
+	ReasonFilterNotSupported Reason = "ORDER_FILTER_NOT_SUPPORTED"
+
+	ReasonDraftStale        Reason = "ORDER_DRAFT_STALE"
+	ReasonDraftNotOwned     Reason = "ORDER_DRAFT_NOT_OWNED"
+	ReasonDraftDuplicateRef Reason = "ORDER_DRAFT_DUPLICATE_REF_ID"
+	ReasonDraftUpgrade      Reason = "ORDER_DRAFT_UPGRADE_FAILED"
+	ReasonDraftTTLInvalid   Reason = "ORDER_DRAFT_TTL_INVALID"
+	ReasonAssigneeNotInTeam Reason = "ORDER_ASSIGNEE_NOT_IN_TEAM"
+	ReasonShopNotInTeam     Reason = "ORDER_SHOP_NOT_IN_TEAM"
+	ReasonImportInvalid     Reason = "ORDER_IMPORT_INVALID"
+	ReasonImportTooLarge    Reason = "ORDER_IMPORT_TOO_LARGE"
+	ReasonInvalidTimezone   Reason = "ORDER_INVALID_TIMEZONE"
+
+	ReasonTagEmptyName    Reason = "ORDER_TAG_EMPTY_NAME"
+	ReasonTagInvalidColor Reason = "ORDER_TAG_INVALID_COLOR"
+	ReasonTagMergeSelf    Reason = "ORDER_TAG_MERGE_SELF"
+	ReasonViewNotFound    Reason = "ORDER_VIEW_NOT_FOUND"
+	ReasonViewEmptyName   Reason = "ORDER_VIEW_EMPTY_NAME"
+	ReasonRuleNotFound    Reason = "ORDER_TAG_RULE_NOT_FOUND"
+	ReasonRuleInvalid     Reason = "ORDER_TAG_RULE_INVALID"
+)
+
+// sentinel untuk errors.Is, dibandingkan berdasarkan reason
+var (
+	ErrOrderNotFound  = &Error{Reason: ReasonOrderNotFound}
+	ErrWrongTeam      = &Error{Reason: ReasonWrongTeam}
+	ErrIllegalStatus  = &Error{Reason: ReasonIllegalStatus}
+	ErrDuplicateRefID = &Error{Reason: ReasonDuplicateRefID}
+	ErrLockConflict   = &Error{Reason: ReasonLockConflict}
+	ErrTagTooLong     = &Error{Reason: ReasonTagTooLong}
+	ErrTagNotFound    = &Error{Reason: ReasonTagNotFound}
+	ErrTagDuplicate   = &Error{Reason: ReasonTagDuplicate}
+	ErrTagTypeDenied  = &Error{Reason: ReasonTagTypeDenied}
+
+	ErrEditExpired        = &Error{Reason: ReasonEditExpired}
+	ErrCurrencyMismatch   = &Error{Reason: ReasonCurrencyMismatch}
+	ErrReturnNotFound     = &Error{Reason: ReasonReturnNotFound}
+	ErrZeroAmount         = &Error{Reason: ReasonZeroAmount}
+	ErrMissingIdentifier  = &Error{Reason: ReasonMissingIdentifier}
+	ErrMarketplaceNotSet  = &Error{Reason: ReasonMarketplaceNotSet}
+	ErrFilterNotSupported = &Error{Reason: ReasonFilterNotSupported}
+
+	ErrDraftStale        = &Error{Reason: ReasonDraftStale}
+	ErrDraftNotOwned     = &Error{Reason: ReasonDraftNotOwned}
+	ErrDraftDuplicateRef = &Error{Reason: ReasonDraftDuplicateRef}
+	ErrDraftUpgrade      = &Error{Reason: ReasonDraftUpgrade}
+	ErrDraftTTLInvalid   = &Error{Reason: ReasonDraftTTLInvalid}
+	ErrAssigneeNotInTeam = &Error{Reason: ReasonAssigneeNotInTeam}
+	ErrShopNotInTeam     = &Error{Reason: ReasonShopNotInTeam}
+	ErrImportInvalid     = &Error{Reason: ReasonImportInvalid}
+	ErrImportTooLarge    = &Error{Reason: ReasonImportTooLarge}
+	ErrInvalidTimezone   = &Error{Reason: ReasonInvalidTimezone}
+
+	ErrTagEmptyName    = &Error{Reason: ReasonTagEmptyName}
+	ErrTagInvalidColor = &Error{Reason: ReasonTagInvalidColor}
+	ErrTagMergeSelf    = &Error{Reason: ReasonTagMergeSelf}
+	ErrViewNotFound    = &Error{Reason: ReasonViewNotFound}
+	ErrViewEmptyName   = &Error{Reason: ReasonViewEmptyName}
+	ErrRuleNotFound    = &Error{Reason: ReasonRuleNotFound}
+	ErrRuleInvalid     = &Error{Reason: ReasonRuleInvalid}
+)
+
+// Error error domain order, metadata dipakai untuk ErrorInfo dan isi pesan terjemahan
+type Error struct {
+	Reason   Reason
+	Metadata map[string]string
+	Err      error
+}
+
+func (e *Error) Error() string {
+	msg := message(e.Reason, English, e.Metadata)
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s", msg, e.Err)
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Reason == e.Reason
+}
+
+func (e *Error) Code() connect.Code {
+	entry, ok := catalogue[e.Reason]
+	if !ok {
+		return connect.CodeUnknown
+	}
+	return entry.code
+}
+
+func newError(reason Reason, err error, kv ...string) *Error {
+	meta := map[string]string{}
+	for i := 0; i+1 < len(kv); i += 2 {
+		if kv[i+1] == "" || kv[i+1] == "0" {
+			continue
+		}
+		meta[kv[i]] = kv[i+1]
+	}
+
+	return &Error{
+		Reason:   reason,
+		Metadata: meta,
+		Err:      err,
+	}
+}
+
+func id(v uint64) string {
+	return strconv.FormatUint(v, 10)
+}
+
+func OrderNotFound(orderID uint64, refID string) *Error {
+	return newError(ReasonOrderNotFound, nil,
+		"order_id", id(orderID),
+		"order_ref_id", refID,
+	)
+}
+
+func WrongTeam(orderID uint64, teamID uint64) *Error {
+	return newError(ReasonWrongTeam, nil,
+		"order_id", id(orderID),
+		"team_id", id(teamID),
+	)
+}
+
+func IllegalStatus(orderID uint64, refID string, status string, want string) *Error {
+	return newError(ReasonIllegalStatus, nil,
+		"order_id", id(orderID),
+		"order_ref_id", refID,
+		"status", status,
+		"want", want,
+	)
+}
+
+func DuplicateRefID(orderID uint64, refID string) *Error {
+	return newError(ReasonDuplicateRefID, nil,
+		"order_id", id(orderID),
+		"order_ref_id", refID,
+	)
+}
+
+func LockConflict(orderID uint64, refID string, err error) *Error {
+	return newError(ReasonLockConflict, err,
+		"order_id", id(orderID),
+		"order_ref_id", refID,
+	)
+}
+
+func TagTooLong(tag string, max int) *Error {
+	return newError(ReasonTagTooLong, nil,
+		"tag", tag,
+		"max", strconv.Itoa(max),
+	)
+}
+
+func TagNotFound(tagID uint64) *Error {
+	return newError(ReasonTagNotFound, nil,
+		"tag_id", id(tagID),
+	)
+}
+
+func TagDuplicate(tagID uint64, name string) *Error {
+	return newError(ReasonTagDuplicate, nil,
+		"tag_id", id(tagID),
+		"tag", name,
+	)
+}
+
+func TagTypeDenied(tagType string, tag string) *Error {
+	return newError(ReasonTagTypeDenied, nil,
+		"tag_type", tagType,
+		"tag", tag,
+	)
+}
+
+func EditExpired(orderID uint64, refID string, days int) *Error {
+	return newError(ReasonEditExpired, nil,
+		"order_id", id(orderID),
+		"order_ref_id", refID,
+		"days", strconv.Itoa(days),
+	)
+}
+
+func CurrencyMismatch(orderID uint64, refID string, currency string, want string) *Error {
+	return newError(ReasonCurrencyMismatch, nil,
+		"order_id", id(orderID),
+		"order_ref_id", refID,
+		"currency", currency,
+		"want", want,
+	)
+}
+
+func ReturnNotFound(txID uint64) *Error {
+	return newError(ReasonReturnNotFound, nil,
+		"tx_id", id(txID),
+	)
+}
+
+func ZeroAmount(orderID uint64) *Error {
+	return newError(ReasonZeroAmount, nil,
+		"order_id", id(orderID),
+	)
+}
+
+func MissingIdentifier() *Error {
+	return newError(ReasonMissingIdentifier, nil)
+}
+
+func MarketplaceNotSet(orderID uint64, refID string) *Error {
+	return newError(ReasonMarketplaceNotSet, nil,
+		"order_id", id(orderID),
+		"order_ref_id", refID,
+	)
+}
+
+func FilterNotSupported() *Error {
+	return newError(ReasonFilterNotSupported, nil)
+}
+
+func DraftStale(draftID uint64) *Error {
+	return newError(ReasonDraftStale, nil,
+		"draft_id", id(draftID),
+	)
+}
+
+func DraftNotOwned(draftID uint64, userID uint64) *Error {
+	return newError(ReasonDraftNotOwned, nil,
+		"draft_id", id(draftID),
+		"user_id", id(userID),
+	)
+}
+
+func DraftDuplicateRef(draftID uint64, refID string) *Error {
+	return newError(ReasonDraftDuplicateRef, nil,
+		"draft_id", id(draftID),
+		"order_ref_id", refID,
+	)
+}
+
+// DraftUpgrade detail alasan payload draft lama tidak bisa dikonversi
+func DraftUpgrade(detail string) *Error {
+	return newError(ReasonDraftUpgrade, nil,
+		"detail", detail,
+	)
+}
+
+func DraftTTLInvalid(days int64) *Error {
+	return newError(ReasonDraftTTLInvalid, nil,
+		"days", strconv.FormatInt(days, 10),
+	)
+}
+
+func AssigneeNotInTeam(userID uint64, teamID uint64) *Error {
+	return newError(ReasonAssigneeNotInTeam, nil,
+		"user_id", id(userID),
+		"team_id", id(teamID),
+	)
+}
+
+func ShopNotInTeam(shopID uint64, teamID uint64) *Error {
+	return newError(ReasonShopNotInTeam, nil,
+		"shop_id", id(shopID),
+		"team_id", id(teamID),
+	)
+}
+
+// ImportInvalid detail alasan file import ditolak
+func ImportInvalid(detail string) *Error {
+	return newError(ReasonImportInvalid, nil,
+		"detail", detail,
+	)
+}
+
+func ImportTooLarge(maxMB int) *Error {
+	return newError(ReasonImportTooLarge, nil,
+		"max", strconv.Itoa(maxMB),
+	)
+}
+
+func InvalidTimezone(timezone string) *Error {
+	return newError(ReasonInvalidTimezone, nil,
+		"timezone", timezone,
+	)
+}
+
+func TagEmptyName() *Error {
+	return newError(ReasonTagEmptyName, nil)
+}
+
+func TagInvalidColor(color string) *Error {
+	return newError(ReasonTagInvalidColor, nil,
+		"color", color,
+	)
+}
+
+func TagMergeSelf(tagID uint64) *Error {
+	return newError(ReasonTagMergeSelf, nil,
+		"tag_id", id(tagID),
+	)
+}
+
+func ViewNotFound(viewID uint64) *Error {
+	return newError(ReasonViewNotFound, nil,
+		"view_id", id(viewID),
+	)
+}
+
+func ViewEmptyName() *Error {
+	return newError(ReasonViewEmptyName, nil)
+}
+
+func RuleNotFound(ruleID uint64) *Error {
+	return newError(ReasonRuleNotFound, nil,
+		"rule_id", id(ruleID),
+	)
+}
+
+// RuleInvalid detail kondisi rule yang tidak valid
+func RuleInvalid(detail string) *Error {
+	return newError(ReasonRuleInvalid, nil,
+		"detail", detail,
+	)
+}
+
+func RecordNotFound(err error) *Error {
+	return newError(ReasonRecordNotFound, err)
+}
+
+// database error asli tidak dikirim ke client, hanya dicatat di trace
+func Database(err error) *Error {
+	return newError(ReasonDatabase, err)
+}
+
+func As(err error) (*Error, bool) {
+	var oerr *Error
+	ok := errors.As(err, &oerr)
+	return oerr, ok
+}
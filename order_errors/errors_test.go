@@ -0,0 +1,89 @@
+package order_errors_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"gorm.io/gorm"
+)
+
+func TestToConnect(t *testing.T) {
+	t.Run("order not found dengan detail", func(t *testing.T) {
+		err := fmt.Errorf("getting order: %w", order_errors.OrderNotFound(12, "INV-1"))
+		assert.ErrorIs(t, err, order_errors.ErrOrderNotFound)
+
+		cerr := &connect.Error{}
+		assert.True(t, errors.As(order_errors.ToConnect(err, order_errors.English), &cerr))
+		assert.Equal(t, connect.CodeNotFound, cerr.Code())
+		assert.Equal(t, "order INV-1 not found", cerr.Message())
+
+		details := cerr.Details()
+		assert.Len(t, details, 2)
+
+		value, err := details[0].Value()
+		assert.Nil(t, err)
+		info, ok := value.(*errdetails.ErrorInfo)
+		assert.True(t, ok)
+		assert.Equal(t, string(order_errors.ReasonOrderNotFound), info.Reason)
+		assert.Equal(t, "12", info.Metadata["order_id"])
+	})
+
+	t.Run("bahasa dari accept language", func(t *testing.T) {
+		lang := order_errors.Language("id-ID,id;q=0.9,en;q=0.8")
+		assert.Equal(t, "order #5 bukan milik team 3", order_errors.Message(order_errors.WrongTeam(5, 3), lang))
+
+		lang = order_errors.Language("en-US")
+		assert.Equal(t, "order #5 does not belong to team 3", order_errors.Message(order_errors.WrongTeam(5, 3), lang))
+
+		// default bahasa indonesia
+		assert.Equal(t, order_errors.Indonesian, order_errors.Language(""))
+	})
+
+	t.Run("error database tidak bocor", func(t *testing.T) {
+		pgErr := &pgconn.PgError{Code: "23503", Message: "insert or update on table \"orders\" violates foreign key"}
+
+		cerr := &connect.Error{}
+		assert.True(t, errors.As(order_errors.ToConnect(pgErr, order_errors.English), &cerr))
+		assert.Equal(t, connect.CodeInternal, cerr.Code())
+		assert.Equal(t, "database error", cerr.Message())
+
+		assert.True(t, errors.As(order_errors.ToConnect(gorm.ErrRecordNotFound, order_errors.English), &cerr))
+		assert.Equal(t, connect.CodeNotFound, cerr.Code())
+	})
+
+	t.Run("lock nowait jadi aborted", func(t *testing.T) {
+		pgErr := &pgconn.PgError{Code: order_errors.PgLockNotAvailable}
+
+		cerr := &connect.Error{}
+		assert.True(t, errors.As(order_errors.ToConnect(pgErr, order_errors.English), &cerr))
+		assert.Equal(t, connect.CodeAborted, cerr.Code())
+	})
+
+	t.Run("order tanpa marketplace", func(t *testing.T) {
+		cerr := &connect.Error{}
+		assert.True(t, errors.As(order_errors.ToConnect(order_errors.MarketplaceNotSet(7, "INV-7"), order_errors.English), &cerr))
+		assert.Equal(t, connect.CodeFailedPrecondition, cerr.Code())
+		assert.Equal(t, "order INV-7 has no marketplace set", cerr.Message())
+	})
+
+	t.Run("draft stale dengan detail", func(t *testing.T) {
+		err := fmt.Errorf("update draft: %w", order_errors.DraftStale(9))
+		assert.ErrorIs(t, err, order_errors.ErrDraftStale)
+
+		cerr := &connect.Error{}
+		assert.True(t, errors.As(order_errors.ToConnect(err, order_errors.Indonesian), &cerr))
+		assert.Equal(t, connect.CodeAborted, cerr.Code())
+		assert.Equal(t, "draft #9 sudah diubah oleh user lain, muat ulang draft", cerr.Message())
+	})
+
+	t.Run("error lain tidak diubah", func(t *testing.T) {
+		err := errors.New("order INV-2 sudah lebih dari satu minggu")
+		assert.Equal(t, err, order_errors.ToConnect(err, order_errors.English))
+	})
+}
@@ -0,0 +1,118 @@
+package order_errors
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/text/language"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"gorm.io/gorm"
+)
+
+// PgLockNotAvailable kode postgres untuk FOR UPDATE NOWAIT yang gagal
+const PgLockNotAvailable = "55P03"
+
+// normalize error gorm dan postgres supaya tidak sampai ke client mentah
+func normalize(err error) error {
+	if _, ok := As(err); ok {
+		return err
+	}
+
+	var cerr *connect.Error
+	if errors.As(err, &cerr) {
+		return err
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return RecordNotFound(err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		if pgErr.Code == PgLockNotAvailable {
+			return LockConflict(0, "", err)
+		}
+		return Database(err)
+	}
+
+	return err
+}
+
+// ToConnect mengubah error katalog jadi connect error dengan ErrorInfo dan pesan sesuai bahasa
+func ToConnect(err error, lang language.Tag) error {
+	if err == nil {
+		return nil
+	}
+
+	err = normalize(err)
+	oerr, ok := As(err)
+	if !ok {
+		return err
+	}
+
+	msg := message(oerr.Reason, lang, oerr.Metadata)
+	cerr := connect.NewError(oerr.Code(), errors.New(msg))
+
+	info, derr := connect.NewErrorDetail(&errdetails.ErrorInfo{
+		Reason:   string(oerr.Reason),
+		Domain:   Domain,
+		Metadata: oerr.Metadata,
+	})
+	if derr == nil {
+		cerr.AddDetail(info)
+	}
+
+	localized, derr := connect.NewErrorDetail(&errdetails.LocalizedMessage{
+		Locale:  lang.String(),
+		Message: msg,
+	})
+	if derr == nil {
+		cerr.AddDetail(localized)
+	}
+
+	return cerr
+}
+
+type errorInterceptor struct{}
+
+func NewInterceptor() connect.Interceptor {
+	return &errorInterceptor{}
+}
+
+func (*errorInterceptor) translate(ctx context.Context, err error, acceptLanguage string) error {
+	if err == nil {
+		return nil
+	}
+
+	// error asli tetap dicatat di trace
+	trace.SpanFromContext(ctx).RecordError(err)
+	return ToConnect(err, Language(acceptLanguage))
+}
+
+// WrapStreamingClient implements connect.Interceptor.
+func (*errorInterceptor) WrapStreamingClient(handler connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return handler
+}
+
+// WrapStreamingHandler implements connect.Interceptor.
+func (e *errorInterceptor) WrapStreamingHandler(handler connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, shc connect.StreamingHandlerConn) error {
+		err := handler(ctx, shc)
+		return e.translate(ctx, err, shc.RequestHeader().Get("Accept-Language"))
+	}
+}
+
+// WrapUnary implements connect.Interceptor.
+func (e *errorInterceptor) WrapUnary(handler connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, ar connect.AnyRequest) (connect.AnyResponse, error) {
+		res, err := handler(ctx, ar)
+		if err == nil || ar.Spec().IsClient {
+			return res, err
+		}
+
+		return res, e.translate(ctx, err, ar.Header().Get("Accept-Language"))
+	}
+}
@@ -46,6 +46,111 @@ func (o *orderServiceMock) OrderCreate(context.Context, *connect.Request[order_i
 	panic("unimplemented")
 }
 
+// OrderProfitLoss implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) OrderProfitLoss(context.Context, *connect.Request[order_iface.OrderProfitLossRequest]) (*connect.Response[order_iface.OrderProfitLossResponse], error) {
+	panic("unimplemented")
+}
+
+// OrderTimeline implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) OrderTimeline(context.Context, *connect.Request[order_iface.OrderTimelineRequest]) (*connect.Response[order_iface.OrderTimelineResponse], error) {
+	panic("unimplemented")
+}
+
+// OrderTagHistoryList implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) OrderTagHistoryList(context.Context, *connect.Request[order_iface.OrderTagHistoryListRequest]) (*connect.Response[order_iface.OrderTagHistoryListResponse], error) {
+	panic("unimplemented")
+}
+
+// OrderTagCount implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) OrderTagCount(context.Context, *connect.Request[order_iface.OrderTagCountRequest]) (*connect.Response[order_iface.OrderTagCountResponse], error) {
+	panic("unimplemented")
+}
+
+// OrderViewList implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) OrderViewList(context.Context, *connect.Request[order_iface.OrderViewListRequest]) (*connect.Response[order_iface.OrderViewListResponse], error) {
+	panic("unimplemented")
+}
+
+// OrderViewSave implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) OrderViewSave(context.Context, *connect.Request[order_iface.OrderViewSaveRequest]) (*connect.Response[order_iface.OrderViewSaveResponse], error) {
+	panic("unimplemented")
+}
+
+// OrderViewDelete implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) OrderViewDelete(context.Context, *connect.Request[order_iface.OrderViewDeleteRequest]) (*connect.Response[order_iface.OrderViewDeleteResponse], error) {
+	panic("unimplemented")
+}
+
+// OrderTagRuleList implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) OrderTagRuleList(context.Context, *connect.Request[order_iface.OrderTagRuleListRequest]) (*connect.Response[order_iface.OrderTagRuleListResponse], error) {
+	panic("unimplemented")
+}
+
+// OrderTagRuleSave implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) OrderTagRuleSave(context.Context, *connect.Request[order_iface.OrderTagRuleSaveRequest]) (*connect.Response[order_iface.OrderTagRuleSaveResponse], error) {
+	panic("unimplemented")
+}
+
+// OrderTagRuleDelete implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) OrderTagRuleDelete(context.Context, *connect.Request[order_iface.OrderTagRuleDeleteRequest]) (*connect.Response[order_iface.OrderTagRuleDeleteResponse], error) {
+	panic("unimplemented")
+}
+
+// OrderTagList implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) OrderTagList(context.Context, *connect.Request[order_iface.OrderTagListRequest]) (*connect.Response[order_iface.OrderTagListResponse], error) {
+	panic("unimplemented")
+}
+
+// OrderTagCreate implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) OrderTagCreate(context.Context, *connect.Request[order_iface.OrderTagCreateRequest]) (*connect.Response[order_iface.OrderTagCreateResponse], error) {
+	panic("unimplemented")
+}
+
+// OrderTagRename implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) OrderTagRename(context.Context, *connect.Request[order_iface.OrderTagRenameRequest]) (*connect.Response[order_iface.OrderTagRenameResponse], error) {
+	panic("unimplemented")
+}
+
+// OrderTagMerge implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) OrderTagMerge(context.Context, *connect.Request[order_iface.OrderTagMergeRequest]) (*connect.Response[order_iface.OrderTagMergeResponse], error) {
+	panic("unimplemented")
+}
+
+// OrderTagArchive implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) OrderTagArchive(context.Context, *connect.Request[order_iface.OrderTagArchiveRequest]) (*connect.Response[order_iface.OrderTagArchiveResponse], error) {
+	panic("unimplemented")
+}
+
+// OrderDraftDeadlineList implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) OrderDraftDeadlineList(context.Context, *connect.Request[order_iface.OrderDraftDeadlineListRequest]) (*connect.Response[order_iface.OrderDraftDeadlineListResponse], error) {
+	panic("unimplemented")
+}
+
+// OrderDraftValidate implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) OrderDraftValidate(context.Context, *connect.Request[order_iface.OrderDraftValidateRequest]) (*connect.Response[order_iface.OrderDraftValidateResponse], error) {
+	panic("unimplemented")
+}
+
+// OrderDraftAssign implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) OrderDraftAssign(context.Context, *connect.Request[order_iface.OrderDraftAssignRequest]) (*connect.Response[order_iface.OrderDraftAssignResponse], error) {
+	panic("unimplemented")
+}
+
+// OrderDraftUpdate implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) OrderDraftUpdate(context.Context, *connect.Request[order_iface.OrderDraftUpdateRequest]) (*connect.Response[order_iface.OrderDraftUpdateResponse], error) {
+	panic("unimplemented")
+}
+
+// OrderDraftImport implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) OrderDraftImport(context.Context, *connect.Request[order_iface.OrderDraftImportRequest]) (*connect.Response[order_iface.OrderDraftImportResponse], error) {
+	panic("unimplemented")
+}
+
+// OrderDraftTTLSet implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) OrderDraftTTLSet(context.Context, *connect.Request[order_iface.OrderDraftTTLSetRequest]) (*connect.Response[order_iface.OrderDraftTTLSetResponse], error) {
+	panic("unimplemented")
+}
+
 // OrderDraftDelete implements order_ifaceconnect.OrderServiceHandler.
 func (o *orderServiceMock) OrderDraftDelete(context.Context, *connect.Request[order_iface.OrderDraftDeleteRequest]) (*connect.Response[order_iface.OrderDraftDeleteResponse], error) {
 	panic("unimplemented")
@@ -81,6 +186,11 @@ func (o *orderServiceMock) MpPaymentDelete(context.Context, *connect.Request[ord
 	panic("unimplemented")
 }
 
+// MpPaymentHistory implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) MpPaymentHistory(context.Context, *connect.Request[order_iface.MpPaymentHistoryRequest]) (*connect.Response[order_iface.MpPaymentHistoryResponse], error) {
+	panic("unimplemented")
+}
+
 // MpPaymentOrderList implements order_ifaceconnect.OrderServiceHandler.
 func (o *orderServiceMock) MpPaymentOrderList(context.Context, *connect.Request[order_iface.MpPaymentOrderListRequest]) (*connect.Response[order_iface.MpPaymentOrderListResponse], error) {
 	panic("unimplemented")
@@ -91,6 +201,11 @@ func (o *orderServiceMock) OrderTagAdd(context.Context, *connect.Request[order_i
 	panic("unimplemented")
 }
 
+// OrderFundSetStream implements order_ifaceconnect.OrderServiceHandler.
+func (o *orderServiceMock) OrderFundSetStream(context.Context, *connect.BidiStream[order_iface.OrderFundSetRequest, order_iface.OrderFundSetStreamResponse]) error {
+	panic("unimplemented")
+}
+
 // OrderFundSet implements order_ifaceconnect.OrderServiceHandler.
 func (o *orderServiceMock) OrderFundSet(context.Context, *connect.ClientStream[order_iface.OrderFundSetRequest]) (*connect.Response[order_iface.OrderFundSetResponse], error) {
 	return &connect.Response[order_iface.OrderFundSetResponse]{}, nil
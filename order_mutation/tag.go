@@ -1,16 +1,30 @@
 package order_mutation
 
 import (
-	"errors"
-
+	"github.com/pdcgo/order_service/order/tag_core"
+	"github.com/pdcgo/order_service/order_errors"
 	"github.com/pdcgo/shared/db_models"
+	"github.com/pdcgo/shared/interfaces/identity_iface"
 	"github.com/pdcgo/shared/interfaces/order_iface"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type tagMutationImpl struct {
-	db *gorm.DB
+	db    *gorm.DB
+	actor *tag_core.TagActor
+}
+
+type TagMutationOption func(t *tagMutationImpl)
+
+// WithActor user yang mengubah tag, dicatat di history tag
+func WithActor(userID uint, from identity_iface.AgentType) TagMutationOption {
+	return func(t *tagMutationImpl) {
+		t.actor = &tag_core.TagActor{
+			UserID: userID,
+			From:   from,
+		}
+	}
 }
 
 // Add implements order_iface.OrderTagMutation.
@@ -24,37 +38,95 @@ func (t *tagMutationImpl) Add(from db_models.RelationFrom, orderIDs []uint, tags
 		return err
 	}
 
-	tagIds, err := t.getTagIds(tags)
+	groups, err := t.scopedTags(from, orderIDs, tags, true)
 	if err != nil {
 		return err
 	}
 
 	relates := []*db_models.OrderTagRelation{}
-	for _, ordID := range orderIDs {
-		for _, tagID := range tagIds {
-			relate := db_models.OrderTagRelation{
-				OrderID:      ordID,
-				OrderTagID:   tagID,
-				RelationFrom: string(db_models.RelationFromTracking),
+	for _, group := range groups {
+		for _, ordID := range group.orderIDs {
+			for _, tagID := range group.tagIDs {
+				relate := db_models.OrderTagRelation{
+					OrderID:      ordID,
+					OrderTagID:   tagID,
+					RelationFrom: string(from),
+				}
+				relates = append(relates, &relate)
 			}
-			relates = append(relates, &relate)
 		}
 	}
 
+	relates, err = t.newRelations(relates)
+	if err != nil {
+		return err
+	}
+
+	if len(relates) == 0 {
+		return nil
+	}
+
+	// order banyak dari bulk tag di insert per batch
 	err = t.db.
 		Clauses(
 			clause.OnConflict{
 				DoNothing: true,
 			},
 		).
-		Save(&relates).
+		CreateInBatches(&relates, 500).
 		Error
 
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return tag_core.RecordHistory(t.db, t.actor, tag_core.TagAdded, relates)
+}
+
+// newRelations buang relasi yang sudah ada supaya history hanya mencatat tag yang benar benar ditambah
+func (t *tagMutationImpl) newRelations(relates []*db_models.OrderTagRelation) ([]*db_models.OrderTagRelation, error) {
+	if len(relates) == 0 {
+		return relates, nil
+	}
+
+	orderIDs := []uint{}
+	tagIDs := []uint{}
+	for _, rel := range relates {
+		orderIDs = append(orderIDs, rel.OrderID)
+		tagIDs = append(tagIDs, rel.OrderTagID)
+	}
+
+	existing := []*db_models.OrderTagRelation{}
+	err := t.db.
+		Model(&db_models.OrderTagRelation{}).
+		Select("order_id", "order_tag_id").
+		Where("order_id IN ?", orderIDs).
+		Where("order_tag_id IN ?", tagIDs).
+		Find(&existing).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	type pair struct{ orderID, tagID uint }
+	found := map[pair]bool{}
+	for _, rel := range existing {
+		found[pair{rel.OrderID, rel.OrderTagID}] = true
+	}
+
+	hasil := []*db_models.OrderTagRelation{}
+	for _, rel := range relates {
+		key := pair{rel.OrderID, rel.OrderTagID}
+		if found[key] {
+			continue
+		}
+
+		found[key] = true
+		hasil = append(hasil, rel)
+	}
+
+	return hasil, nil
 }
 
 // Remove implements order_iface.OrderTagMutation.
@@ -62,83 +134,118 @@ func (t *tagMutationImpl) Remove(from db_models.RelationFrom, orderIDs []uint, t
 	if len(tags) == 0 {
 		return nil
 	}
-	tagIds, err := t.getTagIds(tags)
+
+	groups, err := t.scopedTags(from, orderIDs, tags, false)
 	if err != nil {
 		return err
 	}
 
-	err = t.db.
-		Model(&db_models.OrderTagRelation{}).
-		Where("relation_from = ?", from).
-		Where("order_id in ?", orderIDs).
-		Where("order_tag_id in ?", tagIds).
-		Delete(&db_models.OrderTagRelation{}).
-		Error
+	for _, group := range groups {
+		if len(group.tagIDs) == 0 {
+			continue
+		}
+
+		err = tag_core.RemoveRelations(t.db, t.actor, func(tx *gorm.DB) *gorm.DB {
+			return tx.
+				Where("relation_from = ?", from).
+				Where("order_id in ?", group.orderIDs).
+				Where("order_tag_id in ?", group.tagIDs)
+		})
 
-	return err
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // RemoveAllFrom implements order_iface.OrderTagMutation.
 func (t *tagMutationImpl) RemoveAllFrom(from db_models.RelationFrom, orderIDs []uint) error {
-	err := t.db.
-		Model(&db_models.OrderTagRelation{}).
-		Where("relation_from = ?", from).
-		Where("order_id in ?", orderIDs).
-		Delete(&db_models.OrderTagRelation{}).
-		Error
-
-	return err
+	return tag_core.RemoveRelations(t.db, t.actor, func(tx *gorm.DB) *gorm.DB {
+		return tx.
+			Where("relation_from = ?", from).
+			Where("order_id in ?", orderIDs)
+	})
 }
 
-func (t *tagMutationImpl) getTagIds(tags []string) ([]uint, error) {
-	hasil := []uint{}
-	maptags := map[string]*db_models.OrderTag{}
-	otags := []*db_models.OrderTag{}
+type orderTagGroup struct {
+	orderIDs []uint
+	tagIDs   []uint
+}
 
-	for _, t := range tags {
-		maptags[t] = nil
-	}
+// scopedTags tag dari tracking dan warehouse memakai tag sistem,
+// selain itu tag dicari di team masing masing order
+func (t *tagMutationImpl) scopedTags(from db_models.RelationFrom, orderIDs []uint, tags []string, create bool) ([]*orderTagGroup, error) {
+	groups := []*orderTagGroup{}
+	teams := map[uint][]uint{}
+	teamIDs := []uint{}
+
+	if tag_core.IsSystemFrom(from) {
+		teams[tag_core.SystemTeamID] = orderIDs
+		teamIDs = append(teamIDs, tag_core.SystemTeamID)
+	} else {
+		orders := []*db_models.Order{}
+		err := t.db.
+			Model(&db_models.Order{}).
+			Select("id", "team_id").
+			Where("id IN ?", orderIDs).
+			Order("team_id asc, id asc").
+			Find(&orders).
+			Error
 
-	err := t.db.Model(&db_models.OrderTag{}).Where("name in ?", tags).Find(&otags).Error
-	if err != nil {
-		return hasil, err
-	}
+		if err != nil {
+			return groups, err
+		}
 
-	for _, dd := range otags {
-		item := dd
-		maptags[item.Name] = item
+		for _, ord := range orders {
+			if _, ok := teams[ord.TeamID]; !ok {
+				teamIDs = append(teamIDs, ord.TeamID)
+			}
+			teams[ord.TeamID] = append(teams[ord.TeamID], ord.ID)
+		}
 	}
 
-	for key, tag := range maptags {
-		if maptags[key] != nil {
-			hasil = append(hasil, tag.ID)
-			continue
+	for _, teamID := range teamIDs {
+		var tagIDs []uint
+		var err error
+		if create {
+			tagIDs, err = tag_core.FindOrCreate(t.db, teamID, tags)
+		} else {
+			tagIDs, err = tag_core.Lookup(t.db, teamID, tags)
 		}
 
-		tt := &db_models.OrderTag{
-			Name: key,
-		}
-		err = t.db.Save(tt).Error
 		if err != nil {
-			return hasil, err
+			return groups, err
 		}
 
-		hasil = append(hasil, tt.ID)
+		groups = append(groups, &orderTagGroup{
+			orderIDs: teams[teamID],
+			tagIDs:   tagIDs,
+		})
 	}
-	return hasil, nil
+
+	return groups, nil
 }
 
 func (t *tagMutationImpl) validateTask(tags []string) error {
 	for _, tag := range tags {
-		if len(tag) > 300 {
-			return errors.New("tag kepanjangan")
+		if len(tag) > tag_core.MaxNameLength {
+			return order_errors.TagTooLong(tag, tag_core.MaxNameLength)
 		}
 	}
 	return nil
 }
 
-func NewTagMutation(db *gorm.DB) order_iface.OrderTagMutation {
-	return &tagMutationImpl{
-		db: db,
+func NewTagMutation(db *gorm.DB, opts ...TagMutationOption) order_iface.OrderTagMutation {
+	mut := &tagMutationImpl{
+		db:    db,
+		actor: tag_core.SystemActor,
+	}
+
+	for _, opt := range opts {
+		opt(mut)
 	}
+
+	return mut
 }
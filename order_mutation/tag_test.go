@@ -3,6 +3,7 @@ package order_mutation_test
 import (
 	"testing"
 
+	"github.com/pdcgo/order_service/order/tag_core"
 	"github.com/pdcgo/order_service/order_mutation"
 	"github.com/pdcgo/shared/db_models"
 	"github.com/pdcgo/shared/pkg/moretest"
@@ -18,8 +19,12 @@ func TestTagMutation(t *testing.T) {
 			&db_models.Order{},
 			&db_models.OrderTag{},
 			&db_models.OrderTagRelation{},
+			&tag_core.OrderTagHistory{},
 		)
 		assert.Nil(t, err)
+
+		err = tag_core.Migrate(&db)
+		assert.Nil(t, err)
 		return nil
 	}
 
@@ -28,6 +33,14 @@ func TestTagMutation(t *testing.T) {
 			{
 				ID: 1,
 			},
+			{
+				ID:     2,
+				TeamID: 3,
+			},
+			{
+				ID:     3,
+				TeamID: 4,
+			},
 		}
 
 		err := db.Save(&orders).Error
@@ -95,6 +108,35 @@ func TestTagMutation(t *testing.T) {
 				})
 			})
 
+			t.Run("tag user dipisah per team", func(t *testing.T) {
+				err := tagmut.Add(db_models.RelationFromUser, []uint{2, 3}, []string{"Prioritas"})
+				assert.Nil(t, err)
+
+				relates := []*db_models.OrderTagRelation{}
+				err = db.Model(&db_models.OrderTagRelation{}).Order("order_id asc").Find(&relates).Error
+				assert.Nil(t, err)
+
+				assert.Equal(t, 2, len(relates))
+				assert.NotEqual(t, relates[0].OrderTagID, relates[1].OrderTagID)
+				assert.Equal(t, string(db_models.RelationFromUser), relates[0].RelationFrom)
+
+				err = tagmut.Remove(db_models.RelationFromUser, []uint{2, 3}, []string{"prioritas"})
+				assert.Nil(t, err)
+
+				var count int64
+				err = db.Model(&db_models.OrderTagRelation{}).Count(&count).Error
+				assert.Nil(t, err)
+				assert.Equal(t, int64(0), count)
+
+				histories, err := tag_core.History(&db, 2)
+				assert.Nil(t, err)
+				assert.Len(t, histories, 2)
+				assert.Equal(t, tag_core.TagRemoved, histories[0].Action)
+				assert.Equal(t, tag_core.TagAdded, histories[1].Action)
+				assert.Equal(t, "Prioritas", histories[1].TagName)
+				assert.Equal(t, string(db_models.RelationFromUser), histories[1].RelationFrom)
+			})
+
 		},
 	)
 }
@@ -3,7 +3,9 @@ package order_service
 import (
 	"net/http"
 
+	"connectrpc.com/connect"
 	"github.com/pdcgo/order_service/order"
+	"github.com/pdcgo/order_service/order_errors"
 	"github.com/pdcgo/order_service/report"
 	"github.com/pdcgo/schema/services/order_iface/v1/order_ifaceconnect"
 	"github.com/pdcgo/schema/services/revenue_iface/v1/revenue_ifaceconnect"
@@ -26,17 +28,18 @@ func NewRegister(
 ) RegisterHandler {
 	return func() ServiceReflectNames {
 		grpcReflect := ServiceReflectNames{}
+		errorInterceptor := connect.WithInterceptors(order_errors.NewInterceptor())
 
 		path, handler := order_ifaceconnect.NewOrderServiceHandler(order.NewOrderService(
 			auth,
 			db,
 			revenueService,
 			trackingService,
-		), defaultInterceptor)
+		), defaultInterceptor, errorInterceptor)
 		mux.Handle(path, handler)
 		grpcReflect = append(grpcReflect, order_ifaceconnect.OrderServiceName)
 
-		path, handler = order_ifaceconnect.NewOrderReportServiceHandler(report.NewOrderReportService(db), defaultInterceptor)
+		path, handler = order_ifaceconnect.NewOrderReportServiceHandler(report.NewOrderReportService(db), defaultInterceptor, errorInterceptor)
 		mux.Handle(path, handler)
 		grpcReflect = append(grpcReflect, order_ifaceconnect.OrderReportServiceName)
 
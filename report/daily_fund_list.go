@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/order_errors"
 	"github.com/pdcgo/schema/services/common/v1"
 	"github.com/pdcgo/schema/services/order_iface/v1"
 	"github.com/pdcgo/shared/db_connect"
@@ -31,6 +32,8 @@ func (o *orderReportServiceImpl) DailyFundList(
 	_, err = db_connect.NewQueryChain(db,
 		func(db *gorm.DB, next db_connect.NextFunc) db_connect.NextFunc {
 			return func(query *gorm.DB) (*gorm.DB, error) { // selecting table
+				// view stats dibuat di luar service ini dan belum punya kolom currency,
+				// hold masih dijumlah lintas currency sampai view dipisah per currency
 				switch kind := pay.Filter.(type) {
 				case *order_iface.DailyFundListRequest_TeamFilter:
 					fteam := kind.TeamFilter
@@ -51,7 +54,7 @@ func (o *orderReportServiceImpl) DailyFundList(
 					return next(query)
 
 				default:
-					return query, fmt.Errorf("filter not supported")
+					return query, order_errors.FilterNotSupported()
 				}
 			}
 		},
@@ -0,0 +1,304 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/pdcgo/order_service/money"
+	"github.com/pdcgo/order_service/order_errors"
+	"github.com/pdcgo/schema/services/common/v1"
+	"github.com/pdcgo/schema/services/order_iface/v1"
+	"github.com/pdcgo/shared/db_connect"
+	"github.com/pdcgo/shared/db_models"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// order yang sudah jalan tapi dana marketplace belum masuk
+var unpaidStatuses = []db_models.OrdStatus{
+	db_models.OrdShipped,
+	db_models.OrdCourrierShipped,
+	db_models.OrdCompleted,
+}
+
+// fund_at kosong disimpan sebagai zero time (0001-01-01) atau epoch,
+// adjustment yang belum cair tidak masuk settlement
+var fundAtUnset = time.Date(1970, 1, 2, 0, 0, 0, 0, time.UTC)
+
+// batas baris settlement tanpa page dan batas baris unpaid per request
+const defaultSettlementPageLimit = 100
+
+// ShopSettlementList implements order_ifaceconnect.OrderReportServiceHandler.
+// periode settlement mengikuti fund_at adjustment, sedangkan unpaid mengikuti
+// created_at order karena order yang belum cair belum punya fund_at.
+func (o *orderReportServiceImpl) ShopSettlementList(
+	ctx context.Context,
+	req *connect.Request[order_iface.ShopSettlementListRequest],
+) (*connect.Response[order_iface.ShopSettlementListResponse], error) {
+	var err error
+
+	db := o.db.WithContext(ctx)
+
+	pay := req.Msg
+	result := order_iface.ShopSettlementListResponse{
+		Data:     []*order_iface.ShopSettlementDay{},
+		Unpaid:   []*order_iface.ShopUnpaidValue{},
+		PageInfo: &common.PageInfo{},
+	}
+
+	if pay.TeamId == 0 && pay.ShopId == 0 {
+		return connect.NewResponse(&result), order_errors.FilterNotSupported()
+	}
+
+	days := []*order_iface.ShopSettlementDay{}
+	keys := map[string]*order_iface.ShopSettlementDay{}
+	settlementKey := func(shopID uint64, day time.Time, currency money.Currency) string {
+		return fmt.Sprintf("%d-%s-%s", shopID, day.Format(time.DateOnly), currency.OrDefault())
+	}
+
+	// filter adjustment, dipakai query ringkasan dan rincian per type
+	settlementScope := func(query *gorm.DB) *gorm.DB {
+		query = query.
+			Table("order_adjustments a").
+			Where("a.deleted = ?", false).
+			Where("a.fund_at > ?", fundAtUnset)
+
+		if pay.TeamId != 0 {
+			query = query.
+				Joins("JOIN orders o ON o.id = a.order_id").
+				Where("o.team_id = ?", pay.TeamId)
+		}
+
+		if pay.ShopId != 0 {
+			query = query.Where("a.mp_id = ?", pay.ShopId)
+		}
+
+		if pay.TimeRange != nil {
+			trange := pay.TimeRange
+
+			if trange.EndDate.IsValid() {
+				query = query.Where("a.fund_at <= ?", trange.EndDate.AsTime())
+			}
+
+			if trange.StartDate.IsValid() {
+				query = query.Where("a.fund_at >= ?", trange.StartDate.AsTime())
+			}
+		}
+
+		return query
+	}
+
+	_, err = db_connect.NewQueryChain(db,
+		func(db *gorm.DB, next db_connect.NextFunc) db_connect.NextFunc {
+			return func(query *gorm.DB) (*gorm.DB, error) { // group per shop, hari dan currency
+				query = settlementScope(query).
+					Select([]string{
+						"a.mp_id AS shop_id",
+						"DATE(a.fund_at) AS day",
+						"a.currency",
+						"COUNT(DISTINCT a.order_id) AS order_count",
+						"COUNT(1) AS adj_count",
+						"SUM(a.amount) AS amount",
+					}).
+					Group("a.mp_id, DATE(a.fund_at), a.currency")
+
+				return next(query)
+			}
+		},
+		func(db *gorm.DB, next db_connect.NextFunc) db_connect.NextFunc {
+			return func(query *gorm.DB) (*gorm.DB, error) { // set pagination
+				page := pay.Page
+				if page == nil {
+					page = &common.PageFilter{
+						Page:  1,
+						Limit: defaultSettlementPageLimit,
+					}
+				}
+
+				var err error
+				var paginated *gorm.DB
+
+				paginated, result.PageInfo, err = db_connect.SetPaginationQuery(db, func() (*gorm.DB, error) {
+					return query.Session(&gorm.Session{}), nil
+				}, page)
+
+				if err != nil {
+					return nil, err
+				}
+
+				return next(paginated)
+			}
+		},
+		func(db *gorm.DB, next db_connect.NextFunc) db_connect.NextFunc {
+			return func(query *gorm.DB) (*gorm.DB, error) { // day sort
+				if pay.Sort == order_iface.DaySort_DAY_SORT_ASC {
+					query = query.Order("day ASC")
+				} else {
+					query = query.Order("day DESC")
+				}
+
+				query = query.Order("shop_id ASC").Order("a.currency ASC")
+
+				return next(query)
+			}
+		},
+		func(db *gorm.DB, next db_connect.NextFunc) db_connect.NextFunc {
+			return func(query *gorm.DB) (*gorm.DB, error) {
+				tempdata := []struct {
+					ShopID     uint64
+					Day        time.Time
+					Currency   money.Currency
+					OrderCount int64
+					AdjCount   int64
+					Amount     float64
+				}{}
+
+				err := query.
+					Find(&tempdata).
+					Error
+
+				if err != nil {
+					return query, err
+				}
+
+				for _, data := range tempdata {
+					day := &order_iface.ShopSettlementDay{
+						Day:        timestamppb.New(data.Day),
+						ShopId:     data.ShopID,
+						Currency:   string(data.Currency.OrDefault()),
+						OrderCount: data.OrderCount,
+						AdjCount:   data.AdjCount,
+						Amount:     money.FromFloat(data.Currency, data.Amount).Float(),
+						Types:      []*order_iface.SettlementTypeValue{},
+					}
+
+					days = append(days, day)
+					keys[settlementKey(data.ShopID, data.Day, data.Currency)] = day
+				}
+
+				return next(query)
+			}
+		},
+	)
+
+	if err != nil {
+		return connect.NewResponse(&result), err
+	}
+
+	result.Data = days
+
+	if len(days) != 0 {
+		// rincian per type hanya untuk baris di halaman ini
+		shopIDs := []uint64{}
+		dates := []string{}
+		for _, day := range days {
+			shopIDs = append(shopIDs, day.ShopId)
+			dates = append(dates, day.Day.AsTime().Format(time.DateOnly))
+		}
+
+		types := []struct {
+			ShopID   uint64
+			Day      time.Time
+			Currency money.Currency
+			Type     db_models.AdjustmentType
+			Count    int64
+			Amount   float64
+		}{}
+
+		err = settlementScope(db).
+			Select([]string{
+				"a.mp_id AS shop_id",
+				"DATE(a.fund_at) AS day",
+				"a.currency",
+				"a.type",
+				"COUNT(1) AS count",
+				"SUM(a.amount) AS amount",
+			}).
+			Where("a.mp_id IN ?", shopIDs).
+			Where("DATE(a.fund_at) IN ?", dates).
+			Group("a.mp_id, DATE(a.fund_at), a.currency, a.type").
+			Order("a.type ASC").
+			Find(&types).
+			Error
+
+		if err != nil {
+			return connect.NewResponse(&result), err
+		}
+
+		for _, item := range types {
+			day := keys[settlementKey(item.ShopID, item.Day, item.Currency)]
+			if day == nil {
+				continue
+			}
+
+			day.Types = append(day.Types, &order_iface.SettlementTypeValue{
+				Type:   string(item.Type),
+				Count:  item.Count,
+				Amount: money.FromFloat(item.Currency, item.Amount).Float(),
+			})
+		}
+	}
+
+	// order belum dibayar, satu baris per shop dan currency.
+	// time range yang sama dipakai ke created_at order, bukan fund_at,
+	// jadi unpaid berarti order yang dibuat di periode ini dan belum cair
+	unpaid := []struct {
+		ShopID     uint64
+		Currency   money.Currency
+		OrderCount int64
+		EstAmount  float64
+	}{}
+
+	query := db.
+		Table("orders o").
+		Select([]string{
+			"o.order_mp_id AS shop_id",
+			"o.currency",
+			"COUNT(1) AS order_count",
+			"SUM(o.order_mp_total) AS est_amount",
+		}).
+		Where("o.wd_fund = ?", false).
+		Where("o.status IN ?", unpaidStatuses)
+
+	if pay.TeamId != 0 {
+		query = query.Where("o.team_id = ?", pay.TeamId)
+	}
+
+	if pay.ShopId != 0 {
+		query = query.Where("o.order_mp_id = ?", pay.ShopId)
+	}
+
+	if pay.TimeRange != nil {
+		if pay.TimeRange.EndDate.IsValid() {
+			query = query.Where("o.created_at <= ?", pay.TimeRange.EndDate.AsTime())
+		}
+
+		if pay.TimeRange.StartDate.IsValid() {
+			query = query.Where("o.created_at >= ?", pay.TimeRange.StartDate.AsTime())
+		}
+	}
+
+	err = query.
+		Group("o.order_mp_id, o.currency").
+		Order("o.order_mp_id ASC").
+		Limit(defaultSettlementPageLimit).
+		Find(&unpaid).
+		Error
+
+	if err != nil {
+		return connect.NewResponse(&result), err
+	}
+
+	for _, data := range unpaid {
+		result.Unpaid = append(result.Unpaid, &order_iface.ShopUnpaidValue{
+			ShopId:     data.ShopID,
+			Currency:   string(data.Currency.OrDefault()),
+			OrderCount: data.OrderCount,
+			EstAmount:  money.FromFloat(data.Currency, data.EstAmount).Float(),
+		})
+	}
+
+	return connect.NewResponse(&result), nil
+}